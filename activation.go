@@ -18,6 +18,22 @@ const (
 	ModeMultiLabel Mode = 4
 )
 
+func (m Mode) String() string {
+	switch m {
+	case ModeDefault:
+		return "Default"
+	case ModeMultiClass:
+		return "MultiClass"
+	case ModeRegression:
+		return "Regression"
+	case ModeBinary:
+		return "Binary"
+	case ModeMultiLabel:
+		return "MultiLabel"
+	}
+	return "N/A"
+}
+
 // OutputActivation returns activation corresponding to prediction mode
 func OutputActivation(c Mode) ActivationType {
 	switch c {
@@ -66,6 +82,24 @@ const (
 	ActivationSoftmax ActivationType = 5
 )
 
+func (a ActivationType) String() string {
+	switch a {
+	case ActivationNone:
+		return "None"
+	case ActivationSigmoid:
+		return "Sigmoid"
+	case ActivationTanh:
+		return "Tanh"
+	case ActivationReLU:
+		return "ReLU"
+	case ActivationLinear:
+		return "Linear"
+	case ActivationSoftmax:
+		return "Softmax"
+	}
+	return "N/A"
+}
+
 // Differentiable is an activation function and its first order derivative,
 // where the latter is expressed as a function of the former for efficiency
 type Differentiable interface {
@@ -0,0 +1,33 @@
+package deep
+
+import "fmt"
+
+// Activations computes a forward pass and returns a copy of the outputs of
+// every layer, the last being the prediction
+func (n *Neural) Activations(input []float64) ([][]float64, error) {
+	if err := n.Forward(input); err != nil {
+		return nil, err
+	}
+	activations := make([][]float64, len(n.Layers))
+	for i, l := range n.Layers {
+		activations[i] = l.values()
+	}
+	return activations, nil
+}
+
+// Embed computes a forward pass and returns a copy of the outputs of the
+// given layer, for use as a feature vector. Negative layers count from the
+// output layer, so -2 is the last hidden layer.
+func (n *Neural) Embed(input []float64, layer int) ([]float64, error) {
+	i := layer
+	if i < 0 {
+		i += len(n.Layers)
+	}
+	if i < 0 || i >= len(n.Layers) {
+		return nil, fmt.Errorf("Invalid layer - expected -%d to %d, got: %d", len(n.Layers), len(n.Layers)-1, layer)
+	}
+	if err := n.Forward(input); err != nil {
+		return nil, err
+	}
+	return n.Layers[i].values(), nil
+}
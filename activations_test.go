@@ -0,0 +1,43 @@
+package deep
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Activations(t *testing.T) {
+	rand.Seed(0)
+	n := NewNeural(&Config{
+		Inputs:     2,
+		Layout:     []int{4, 3, 2},
+		Activation: ActivationReLU,
+		Mode:       ModeMultiClass,
+		Weight:     NewNormal(1, 0),
+		Bias:       true,
+	})
+
+	activations, err := n.Activations([]float64{0.5, -1})
+	assert.NoError(t, err)
+	assert.Len(t, activations, 3)
+	assert.Len(t, activations[0], 4)
+	assert.Len(t, activations[1], 3)
+	assert.Equal(t, n.Predict([]float64{0.5, -1}), activations[2])
+
+	hidden, err := n.Embed([]float64{0.5, -1}, -2)
+	assert.NoError(t, err)
+	assert.Equal(t, activations[1], hidden)
+
+	n.Predict([]float64{3, 3})
+	assert.Equal(t, activations[1], hidden, "results are copies")
+
+	first, err := n.Embed([]float64{0.5, -1}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, activations[0], first)
+
+	_, err = n.Embed([]float64{0.5, -1}, 3)
+	assert.EqualError(t, err, "Invalid layer - expected -3 to 2, got: 3")
+	_, err = n.Activations([]float64{1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package deep
+
+import (
+	"math"
+	"strings"
+)
+
+// Histogram counts values in equally wide bins between Min and Max
+type Histogram struct {
+	Min, Max float64
+	Counts   []int
+}
+
+// Sparkline renders the histogram as a row of block characters
+func (h Histogram) Sparkline() string {
+	const blocks = " ▁▂▃▄▅▆▇█"
+	levels := []rune(blocks)
+	var top int
+	for _, c := range h.Counts {
+		top = max(top, c)
+	}
+	var b strings.Builder
+	for _, c := range h.Counts {
+		level := 0
+		if top > 0 {
+			level = int(math.Ceil(float64(c) / float64(top) * float64(len(levels)-1)))
+		}
+		b.WriteRune(levels[level])
+	}
+	return b.String()
+}
+
+// Stats summarizes a set of values
+type Stats struct {
+	N                   int
+	Mean, Std, Min, Max float64
+	Histogram           Histogram
+}
+
+// NewStats computes statistics and a histogram with the given number of bins
+func NewStats(values []float64, bins int) Stats {
+	s := Stats{N: len(values), Min: math.Inf(1), Max: math.Inf(-1)}
+	if len(values) == 0 {
+		s.Min, s.Max = 0, 0
+		return s
+	}
+	for _, v := range values {
+		s.Mean += v
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+	}
+	s.Mean /= float64(len(values))
+	for _, v := range values {
+		s.Std += (v - s.Mean) * (v - s.Mean)
+	}
+	s.Std = math.Sqrt(s.Std / float64(len(values)))
+
+	s.Histogram = Histogram{Min: s.Min, Max: s.Max, Counts: make([]int, max(bins, 1))}
+	width := (s.Max - s.Min) / float64(len(s.Histogram.Counts))
+	for _, v := range values {
+		bin := len(s.Histogram.Counts) - 1
+		if width > 0 {
+			bin = min(int((v-s.Min)/width), bin)
+		}
+		s.Histogram.Counts[bin]++
+	}
+	return s
+}
+
+// WeightStats returns statistics of the non-bias weights of each layer
+func (n *Neural) WeightStats(bins int) []Stats {
+	stats := make([]Stats, len(n.Layers))
+	for i, l := range n.Layers {
+		var weights []float64
+		for _, neuron := range l.Neurons {
+			for _, s := range neuron.In {
+				if !s.IsBias {
+					weights = append(weights, s.Weight)
+				}
+			}
+		}
+		stats[i] = NewStats(weights, bins)
+	}
+	return stats
+}
+
+// ActivationStats summarizes the outputs of a layer over a batch of inputs
+type ActivationStats struct {
+	Stats
+	// Dead is the fraction of ReLU neurons that output zero for every input
+	Dead float64
+	// Saturated is the fraction of sigmoid or tanh outputs within 0.01 of their bounds
+	Saturated float64
+}
+
+// ActivationStats returns statistics of each layer's outputs over inputs
+func (n *Neural) ActivationStats(inputs [][]float64, bins int) ([]ActivationStats, error) {
+	values := make([][]float64, len(n.Layers))
+	alive := make([][]bool, len(n.Layers))
+	for i, l := range n.Layers {
+		alive[i] = make([]bool, len(l.Neurons))
+	}
+	saturated := make([]int, len(n.Layers))
+	for _, input := range inputs {
+		activations, err := n.Activations(input)
+		if err != nil {
+			return nil, err
+		}
+		for i, l := range n.Layers {
+			values[i] = append(values[i], activations[i]...)
+			for j, v := range activations[i] {
+				alive[i][j] = alive[i][j] || v != 0
+				if saturates(l.Neurons[j].A, v) {
+					saturated[i]++
+				}
+			}
+		}
+	}
+
+	stats := make([]ActivationStats, len(n.Layers))
+	for i, l := range n.Layers {
+		stats[i].Stats = NewStats(values[i], bins)
+		if len(values[i]) > 0 {
+			stats[i].Saturated = float64(saturated[i]) / float64(len(values[i]))
+		}
+		if len(inputs) == 0 {
+			continue
+		}
+		var relus, dead int
+		for j, neuron := range l.Neurons {
+			if neuron.A == ActivationReLU {
+				relus++
+				if !alive[i][j] {
+					dead++
+				}
+			}
+		}
+		if relus > 0 {
+			stats[i].Dead = float64(dead) / float64(relus)
+		}
+	}
+	return stats, nil
+}
+
+func saturates(a ActivationType, v float64) bool {
+	switch a {
+	case ActivationSigmoid:
+		return v < 0.01 || v > 0.99
+	case ActivationTanh:
+		return math.Abs(v) > 0.99
+	}
+	return false
+}
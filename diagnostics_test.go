@@ -0,0 +1,49 @@
+package deep
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Stats(t *testing.T) {
+	s := NewStats([]float64{1, 2, 2, 3, 7}, 3)
+	assert.Equal(t, 5, s.N)
+	assert.InDelta(t, 3, s.Mean, 1e-12)
+	assert.InDelta(t, 2.097617696, s.Std, 1e-9)
+	assert.Equal(t, 1., s.Min)
+	assert.Equal(t, 7., s.Max)
+	assert.Equal(t, []int{3, 1, 1}, s.Histogram.Counts)
+	assert.Equal(t, "█▃▃", s.Histogram.Sparkline())
+
+	assert.Equal(t, []int{0, 2}, NewStats([]float64{4, 4}, 2).Histogram.Counts)
+	assert.Equal(t, 0, NewStats(nil, 2).N)
+}
+
+func Test_ActivationStats(t *testing.T) {
+	n := NewNeural(&Config{
+		Inputs:     2,
+		Layout:     []int{2, 1},
+		Activation: ActivationReLU,
+		Mode:       ModeBinary,
+		Bias:       true,
+	})
+	n.ApplyWeights([][][]float64{
+		{{1, 1, 0}, {-1, -1, -1}},
+		{{10, 0, 0}},
+	})
+
+	weights := n.WeightStats(2)
+	assert.Equal(t, 4, weights[0].N)
+	assert.Equal(t, 2, weights[1].N)
+	assert.Equal(t, 0., weights[0].Mean)
+
+	stats, err := n.ActivationStats([][]float64{{1, 2}, {0.5, 0.5}, {0, 0}}, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, stats[0].Dead)
+	assert.Equal(t, 6, stats[0].N)
+	assert.InDelta(t, 2./3, stats[1].Saturated, 1e-12)
+
+	_, err = n.ActivationStats([][]float64{{1}}, 4)
+	assert.Error(t, err)
+}
@@ -0,0 +1,237 @@
+package deep
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Predictor is implemented by everything that maps an input to a prediction
+type Predictor interface {
+	Predict(input []float64) []float64
+}
+
+// Combination determines how an ensemble combines its members' predictions
+type Combination int
+
+const (
+	// CombineMean averages the members' predictions
+	CombineMean Combination = 0
+	// CombineWeighted averages the members' predictions by Weights
+	CombineWeighted Combination = 1
+	// CombineVote returns the (weighted) fraction of members predicting each
+	// class. Single outputs vote for 1 if they round to 1.
+	CombineVote Combination = 2
+	// CombineStack feeds the concatenated members' predictions to Stacker
+	CombineStack Combination = 3
+)
+
+// Ensemble combines networks with the same input and output dimensions
+type Ensemble struct {
+	Members     []*Neural
+	Combination Combination
+	// Weights per member for CombineWeighted and CombineVote, nil weighs members equally
+	Weights []float64
+	// Stacker is the combiner network of CombineStack, taking the members'
+	// predictions concatenated in order
+	Stacker *Neural
+}
+
+// NewEnsemble returns an ensemble averaging the given members
+func NewEnsemble(members ...*Neural) (*Ensemble, error) {
+	e := &Ensemble{Members: members}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Add appends a member with the given weight, where 1 is the weight of
+// members added while Weights is nil
+func (e *Ensemble) Add(n *Neural, weight float64) error {
+	if len(e.Members) > 0 {
+		if err := e.checkMember(n); err != nil {
+			return err
+		}
+	}
+	if e.Weights == nil && weight != 1 {
+		e.Weights = make([]float64, len(e.Members))
+		for i := range e.Weights {
+			e.Weights[i] = 1
+		}
+	}
+	if e.Weights != nil {
+		e.Weights = append(e.Weights, weight)
+	}
+	e.Members = append(e.Members, n)
+	return nil
+}
+
+// Validate checks that members agree on dimensions and that weights and
+// stacker fit the members
+func (e *Ensemble) Validate() error {
+	if len(e.Members) == 0 {
+		return fmt.Errorf("Invalid ensemble - expected at least one member")
+	}
+	for _, m := range e.Members[1:] {
+		if err := e.checkMember(m); err != nil {
+			return err
+		}
+	}
+	if e.Weights != nil {
+		if len(e.Weights) != len(e.Members) {
+			return fmt.Errorf("Invalid ensemble weights - expected: %d got: %d", len(e.Members), len(e.Weights))
+		}
+		var sum float64
+		for i, w := range e.Weights {
+			if w < 0 || !IsFinite(w) {
+				return fmt.Errorf("Invalid ensemble weights - member %d has weight %v", i, w)
+			}
+			sum += w
+		}
+		if sum == 0 {
+			return fmt.Errorf("Invalid ensemble weights - expected a positive sum")
+		}
+	}
+	if e.Combination == CombineStack {
+		if e.Stacker == nil {
+			return fmt.Errorf("Invalid ensemble - stacking requires a stacker")
+		}
+		if inputs := len(e.Members) * e.outputs(); e.Stacker.Config.Inputs != inputs {
+			return fmt.Errorf("Invalid stacker inputs - expected: %d got: %d", inputs, e.Stacker.Config.Inputs)
+		}
+	}
+	return nil
+}
+
+func (e *Ensemble) checkMember(n *Neural) error {
+	first := e.Members[0]
+	if n.Config.Inputs != first.Config.Inputs {
+		return fmt.Errorf("Invalid member inputs - expected: %d got: %d", first.Config.Inputs, n.Config.Inputs)
+	}
+	if outputs := len(n.Layers[len(n.Layers)-1].Neurons); outputs != e.outputs() {
+		return fmt.Errorf("Invalid member outputs - expected: %d got: %d", e.outputs(), outputs)
+	}
+	return nil
+}
+
+func (e *Ensemble) outputs() int {
+	first := e.Members[0]
+	return len(first.Layers[len(first.Layers)-1].Neurons)
+}
+
+// Predict returns the combined prediction of the members
+func (e *Ensemble) Predict(input []float64) []float64 {
+	out, _ := e.PredictE(input)
+	return out
+}
+
+// PredictE returns the combined prediction of the members,
+// or the first error returned by a forward pass
+func (e *Ensemble) PredictE(input []float64) ([]float64, error) {
+	if e.Combination == CombineStack {
+		stacked, err := e.StackInput(input)
+		if err != nil {
+			return nil, err
+		}
+		return e.Stacker.PredictE(stacked)
+	}
+
+	out := make([]float64, e.outputs())
+	var total float64
+	for i, m := range e.Members {
+		prediction, err := m.PredictE(input)
+		if err != nil {
+			return nil, err
+		}
+		w := 1.0
+		if e.Weights != nil && e.Combination != CombineMean {
+			w = e.Weights[i]
+		}
+		total += w
+		if e.Combination == CombineVote {
+			if len(out) == 1 {
+				out[0] += w * float64(Round(prediction[0]))
+			} else {
+				out[ArgMax(prediction)] += w
+			}
+			continue
+		}
+		for j, p := range prediction {
+			out[j] += w * p
+		}
+	}
+	for j := range out {
+		out[j] /= total
+	}
+	return out, nil
+}
+
+// StackInput returns the members' predictions concatenated, the input of the stacker
+func (e *Ensemble) StackInput(input []float64) ([]float64, error) {
+	var stacked []float64
+	for _, m := range e.Members {
+		prediction, err := m.PredictE(input)
+		if err != nil {
+			return nil, err
+		}
+		stacked = append(stacked, prediction...)
+	}
+	return stacked, nil
+}
+
+// EnsembleDump is an ensemble dump, holding a network dump per member
+type EnsembleDump struct {
+	Members     []*Dump
+	Combination Combination
+	Weights     []float64 `json:",omitempty"`
+	Stacker     *Dump     `json:",omitempty"`
+}
+
+// Dump generates an ensemble dump
+func (e *Ensemble) Dump() *EnsembleDump {
+	dump := &EnsembleDump{Combination: e.Combination, Weights: e.Weights}
+	for _, m := range e.Members {
+		dump.Members = append(dump.Members, m.Dump())
+	}
+	if e.Stacker != nil {
+		dump.Stacker = e.Stacker.Dump()
+	}
+	return dump
+}
+
+// FromEnsembleDump restores an ensemble from a dump
+func FromEnsembleDump(dump *EnsembleDump) (*Ensemble, error) {
+	e := &Ensemble{Combination: dump.Combination, Weights: dump.Weights}
+	for i, d := range dump.Members {
+		m, err := FromDumpE(d)
+		if err != nil {
+			return nil, fmt.Errorf("member %d: %w", i, err)
+		}
+		e.Members = append(e.Members, m)
+	}
+	if dump.Stacker != nil {
+		stacker, err := FromDumpE(dump.Stacker)
+		if err != nil {
+			return nil, fmt.Errorf("stacker: %w", err)
+		}
+		e.Stacker = stacker
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Marshal marshals to JSON from ensemble
+func (e *Ensemble) Marshal() ([]byte, error) {
+	return json.Marshal(e.Dump())
+}
+
+// UnmarshalEnsemble restores an ensemble from a JSON blob
+func UnmarshalEnsemble(bytes []byte) (*Ensemble, error) {
+	var dump EnsembleDump
+	if err := json.Unmarshal(bytes, &dump); err != nil {
+		return nil, err
+	}
+	return FromEnsembleDump(&dump)
+}
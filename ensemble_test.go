@@ -0,0 +1,100 @@
+package deep
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ensembleMember(seed int64, outputs int) *Neural {
+	rand.Seed(seed)
+	return NewNeural(&Config{
+		Inputs:     2,
+		Layout:     []int{3, outputs},
+		Activation: ActivationTanh,
+		Mode:       ModeMultiClass,
+		Weight:     NewNormal(1, 0),
+		Bias:       true,
+	})
+}
+
+func Test_Ensemble(t *testing.T) {
+	a, b, c := ensembleMember(1, 3), ensembleMember(2, 3), ensembleMember(3, 3)
+	e, err := NewEnsemble(a, b, c)
+	assert.NoError(t, err)
+
+	input := []float64{0.4, -0.2}
+	pa, pb, pc := a.Predict(input), b.Predict(input), c.Predict(input)
+	var _ Predictor = e
+
+	mean := e.Predict(input)
+	for j := range mean {
+		assert.InDelta(t, (pa[j]+pb[j]+pc[j])/3, mean[j], 1e-12)
+	}
+
+	e.Combination, e.Weights = CombineWeighted, []float64{2, 1, 1}
+	weighted := e.Predict(input)
+	for j := range weighted {
+		assert.InDelta(t, (2*pa[j]+pb[j]+pc[j])/4, weighted[j], 1e-12)
+	}
+
+	e.Combination, e.Weights = CombineVote, nil
+	votes := e.Predict(input)
+	expected := make([]float64, 3)
+	for _, p := range [][]float64{pa, pb, pc} {
+		expected[ArgMax(p)] += 1. / 3
+	}
+	assert.InDeltaSlice(t, expected, votes, 1e-12)
+
+	assert.EqualError(t, e.Add(ensembleMember(4, 2), 1), "Invalid member outputs - expected: 3 got: 2")
+	assert.NoError(t, e.Add(ensembleMember(4, 3), 3))
+	assert.Equal(t, []float64{1, 1, 1, 3}, e.Weights)
+
+	e.Combination = CombineStack
+	assert.EqualError(t, e.Validate(), "Invalid ensemble - stacking requires a stacker")
+	_, err = NewEnsemble()
+	assert.Error(t, err)
+}
+
+func Test_MarshalEnsemble(t *testing.T) {
+	e, err := NewEnsemble(ensembleMember(1, 2), ensembleMember(2, 2))
+	assert.NoError(t, err)
+	e.Combination = CombineStack
+	e.Stacker = NewNeural(&Config{
+		Inputs: 4,
+		Layout: []int{2},
+		Mode:   ModeMultiClass,
+		Bias:   true,
+	})
+	assert.NoError(t, e.Validate())
+
+	dump, err := e.Marshal()
+	assert.NoError(t, err)
+	restored, err := UnmarshalEnsemble(dump)
+	assert.NoError(t, err)
+	assert.Equal(t, CombineStack, restored.Combination)
+	assert.Len(t, restored.Members, 2)
+	assert.Equal(t, e.Predict([]float64{1, 0}), restored.Predict([]float64{1, 0}))
+
+	e.Stacker.Config.Inputs = 3
+	dump, _ = e.Marshal()
+	_, err = UnmarshalEnsemble(dump)
+	assert.Error(t, err)
+}
+
+func Test_Clone(t *testing.T) {
+	n := ensembleMember(1, 2)
+	n.Prune(0.3, PruneGlobal)
+	n.Freeze(0)
+
+	clone := n.Clone()
+	assert.Equal(t, n.Weights(), clone.Weights())
+	assert.Equal(t, n.Sparsity(), clone.Sparsity())
+	assert.True(t, clone.IsFrozen(0))
+
+	clone.Layers[0].Neurons[0].In[0].Weight = 42
+	clone.Config.Layout[0] = 7
+	assert.NotEqual(t, 42., n.Layers[0].Neurons[0].In[0].Weight)
+	assert.Equal(t, 3, n.Config.Layout[0])
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/patrikeh/go-deep/metrics"
 	"github.com/patrikeh/go-deep/training"
 
 	deep "github.com/patrikeh/go-deep"
@@ -35,26 +36,26 @@ func main() {
 		panic(err)
 	}
 
-	for i := range train {
-		for j := range train[i].Input {
-			train[i].Input[j] = train[i].Input[j] / 255
-		}
-	}
-	for i := range test {
-		for j := range test[i].Input {
-			test[i].Input[j] = test[i].Input[j] / 255
-		}
+	// pixels are scaled from 0-255 to (0,1) by the network, so predictions take raw
+	// intensities. The range is fixed rather than fitted, as border pixels that are
+	// blank throughout the training set would otherwise be left unscaled.
+	pixels := len(train[0].Input)
+	scaler := &deep.Scaler{Type: deep.ScaleMinMax, Center: make([]float64, pixels), Scale: make([]float64, pixels)}
+	for i := range scaler.Scale {
+		scaler.Scale[i] = 255
 	}
+
 	test.Shuffle()
 	train.Shuffle()
 
 	neural := deep.NewNeural(&deep.Config{
-		Inputs:     len(train[0].Input),
-		Layout:     []int{50, 10},
-		Activation: deep.ActivationReLU,
-		Mode:       deep.ModeMultiClass,
-		Weight:     deep.NewNormal(0.6, 0.1), // slight positive bias helps ReLU
-		Bias:       true,
+		Inputs:      pixels,
+		Layout:      []int{50, 10},
+		Activation:  deep.ActivationReLU,
+		Mode:        deep.ModeMultiClass,
+		Weight:      deep.NewNormal(0.6, 0.1), // slight positive bias helps ReLU
+		Bias:        true,
+		InputScaler: scaler,
 	})
 
 	//trainer := training.NewBatchTrainer(training.NewSGD(0.01, 0.5, 1e-6, true), 1, 200, 8)
@@ -99,14 +100,27 @@ func main() {
 
 		if epoch < epochs-1 {
 			trainStart := time.Now()
-			trainer.Train(neural, train, test, iterations)
+			if err := trainer.Train(neural, train, test, iterations); err != nil {
+				panic(err)
+			}
 			fmt.Printf("train time: %s/%s\n", time.Since(trainStart), time.Since(start))
 		}
 	}
+
+	results := metrics.Evaluate(neural, test)
+	fmt.Printf("\nmacro F1: %.4f\n%s", results.Confusion().MacroF1(), results.Confusion())
+
+	report, err := training.Robustness(neural, test[:1000], []float64{0, 0.05, 0.1, 0.2}, func(eps float64) training.Attack {
+		return training.FGSM{Epsilon: eps * 255, Clip: true, Min: 0, Max: 255}
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("\naccuracy under FGSM attack:\n%s", report)
 }
 
 func toColor(in float64) lipgloss.Color {
-	n := int(in * 255)
+	n := int(in)
 	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", n, n, n))
 }
 
@@ -0,0 +1,148 @@
+package deep
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Head is an output head of a multi-task network. Heads own consecutive
+// neurons of the output layer, and responses are the heads' targets concatenated.
+type Head struct {
+	Name    string
+	Outputs int
+	// Mode determines the head's output activation and default loss
+	Mode Mode
+	Loss LossType
+	// LossOptions parameterize the head's loss
+	LossOptions LossOptions
+	// Weight scales the head's loss, 0 counts as 1
+	Weight float64 `json:",omitempty"`
+}
+
+// OutputHeads returns the heads of the output layer with default losses and
+// weights filled in. Networks without Heads have a single head spanning the layer.
+func (c *Config) OutputHeads() []Head {
+	if len(c.Heads) == 0 {
+		return []Head{{
+			Outputs:     c.Layout[len(c.Layout)-1],
+			Mode:        c.Mode,
+			Loss:        cmp.Or(c.Loss, defaultLoss(c.Mode)),
+			LossOptions: c.LossOptions,
+			Weight:      1,
+		}}
+	}
+	heads := make([]Head, len(c.Heads))
+	for i, h := range c.Heads {
+		h.Loss = cmp.Or(h.Loss, defaultLoss(h.Mode))
+		h.Weight = cmp.Or(h.Weight, 1)
+		heads[i] = h
+	}
+	return heads
+}
+
+// HeadActivation returns the output activation of head h
+func (c *Config) HeadActivation(h Head) ActivationType {
+	return cmp.Or(OutputActivation(h.Mode), c.Activation, ActivationSigmoid)
+}
+
+// HeadLoss returns the loss function of head h. It panics if the
+// loss or its options are invalid, which Validate reports as an error.
+func (c *Config) HeadLoss(h Head) Loss {
+	l, err := NewLoss(h.Loss, h.LossOptions)
+	if err != nil {
+		panic("deep: " + err.Error())
+	}
+	return l
+}
+
+// SplitHeads splits an output or response vector into per-head slices
+func (c *Config) SplitHeads(out []float64) [][]float64 {
+	spans := c.spans()
+	split := make([][]float64, len(spans))
+	for i, s := range spans {
+		split[i] = out[s.start:s.end]
+	}
+	return split
+}
+
+// PredictHeads computes a forward pass and returns the prediction of each head
+func (n *Neural) PredictHeads(input []float64) [][]float64 {
+	return n.Config.SplitHeads(n.Predict(input))
+}
+
+// span is the range of output neurons belonging to a head
+type span struct {
+	start, end int
+	a          ActivationType
+}
+
+func (c *Config) spans() []span {
+	heads := c.OutputHeads()
+	spans := make([]span, len(heads))
+	var start int
+	for i, h := range heads {
+		spans[i] = span{start: start, end: start + h.Outputs, a: c.HeadActivation(h)}
+		start += h.Outputs
+	}
+	return spans
+}
+
+func newHeadLayer(c *Config) *Layer {
+	l := &Layer{A: ActivationNone, spans: c.spans()}
+	for _, s := range l.spans {
+		act := s.a
+		if act == ActivationSoftmax {
+			act = ActivationLinear
+		}
+		for j := s.start; j < s.end; j++ {
+			l.Neurons = append(l.Neurons, NewNeuron(act))
+		}
+	}
+	return l
+}
+
+func (c *Config) validateHead(h Head) error {
+	if h.Mode < ModeDefault || h.Mode > ModeMultiLabel {
+		return fmt.Errorf("Invalid mode: %d", h.Mode)
+	}
+	if h.Mode == ModeMultiClass && h.Outputs < 2 {
+		return fmt.Errorf("Invalid layout - %s requires at least 2 outputs, got: %d", h.Mode, h.Outputs)
+	}
+	loss := cmp.Or(h.Loss, defaultLoss(h.Mode))
+	l, err := NewLoss(loss, h.LossOptions)
+	if err != nil {
+		return err
+	}
+	if output := c.HeadActivation(h); !Compatible(l, output) {
+		return fmt.Errorf("Invalid loss - %s is not compatible with %s output in %s mode", loss, output, h.Mode)
+	}
+	return nil
+}
+
+func (c *Config) validateHeads(outputs int) error {
+	if c.Mode != ModeDefault || c.Loss != LossNone || c.LossOptions != (LossOptions{}) || c.ClassWeights != nil {
+		return fmt.Errorf("Invalid heads - mode, loss and class weights are set per head")
+	}
+	var total int
+	names := map[string]bool{}
+	for _, h := range c.Heads {
+		if names[h.Name] {
+			return fmt.Errorf("Invalid heads - duplicate name %q", h.Name)
+		}
+		names[h.Name] = true
+		if h.Outputs <= 0 {
+			return fmt.Errorf("Invalid head %q - expected positive outputs, got: %d", h.Name, h.Outputs)
+		}
+		if h.Weight < 0 || !IsFinite(h.Weight) {
+			return fmt.Errorf("Invalid head %q - weight %v", h.Name, h.Weight)
+		}
+		if err := c.validateHead(h); err != nil {
+			return fmt.Errorf("Invalid head %q: %w", h.Name, err)
+		}
+		total += h.Outputs
+	}
+	if total != outputs {
+		return fmt.Errorf("Invalid heads - expected %d outputs in total, got: %d", outputs, total)
+	}
+	return nil
+}
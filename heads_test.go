@@ -0,0 +1,80 @@
+package deep
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func headsNetwork() *Neural {
+	rand.Seed(0)
+	return NewNeural(&Config{
+		Inputs:     3,
+		Layout:     []int{4, 6},
+		Activation: ActivationTanh,
+		Weight:     NewNormal(1, 0),
+		Bias:       true,
+		Heads: []Head{
+			{Name: "digit", Outputs: 3, Mode: ModeMultiClass},
+			{Name: "thickness", Outputs: 1, Mode: ModeRegression, Weight: 0.5},
+			{Name: "labels", Outputs: 2, Mode: ModeMultiLabel},
+		},
+	})
+}
+
+func Test_Heads(t *testing.T) {
+	n := headsNetwork()
+	assert.NoError(t, n.Config.Validate())
+	assert.Equal(t, LossCrossEntropy, n.Config.Heads[0].Loss)
+	assert.Equal(t, LossMeanSquared, n.Config.Heads[1].Loss)
+	assert.Equal(t, LossBinaryCrossEntropy, n.Config.Heads[2].Loss)
+	assert.Equal(t, []float64{1, 0.5, 1}, []float64{
+		n.Config.OutputHeads()[0].Weight, n.Config.OutputHeads()[1].Weight, n.Config.OutputHeads()[2].Weight,
+	})
+
+	out := n.Layers[1]
+	assert.Equal(t, ActivationLinear, out.Neurons[0].A)
+	assert.Equal(t, ActivationLinear, out.Neurons[3].A)
+	assert.Equal(t, ActivationSigmoid, out.Neurons[5].A)
+	assert.Len(t, out.Neurons[3].In, 5)
+
+	heads := n.PredictHeads([]float64{0.5, -1, 2})
+	assert.Len(t, heads, 3)
+	assert.Len(t, heads[0], 3)
+	assert.InDelta(t, 1, heads[0][0]+heads[0][1]+heads[0][2], 1e-12)
+	assert.Len(t, heads[1], 1)
+	for _, v := range heads[2] {
+		assert.True(t, v > 0 && v < 1)
+	}
+
+	assert.InDeltaSlice(t, n.Predict([]float64{0.5, -1, 2}), n.Sparse().Predict([]float64{0.5, -1, 2}), 1e-12)
+
+	dump, err := n.Marshal()
+	assert.NoError(t, err)
+	restored, err := Unmarshal(dump)
+	assert.NoError(t, err)
+	assert.Equal(t, n.Config.Heads, restored.Config.Heads)
+	assert.Equal(t, n.Predict([]float64{1, 2, 3}), restored.Predict([]float64{1, 2, 3}))
+}
+
+func Test_ValidateHeads(t *testing.T) {
+	tests := []struct {
+		config func(c *Config)
+		err    string
+	}{
+		{func(c *Config) { c.Layout[1] = 5 }, "Invalid heads - expected 5 outputs in total, got: 6"},
+		{func(c *Config) { c.Mode = ModeMultiClass }, "Invalid heads - mode, loss and class weights are set per head"},
+		{func(c *Config) { c.LossOptions.Smoothing = 0.1 }, "Invalid heads - mode, loss and class weights are set per head"},
+		{func(c *Config) { c.Heads[1].Name = "digit" }, `Invalid heads - duplicate name "digit"`},
+		{func(c *Config) { c.Heads[1].Weight = -1 }, `Invalid head "thickness" - weight -1`},
+		{func(c *Config) { c.Heads[1].LossOptions.Gamma = 1 }, `Invalid head "thickness": Invalid loss options - gamma only applies to Focal, got: MSE`},
+		{func(c *Config) { c.Heads[2].Loss = LossCrossEntropy }, `Invalid head "labels": Invalid loss - CE is not compatible with Sigmoid output in MultiLabel mode`},
+	}
+	for _, test := range tests {
+		c := *headsNetwork().Config
+		c.Heads = append([]Head(nil), c.Heads...)
+		test.config(&c)
+		assert.EqualError(t, c.Validate(), test.err)
+	}
+}
@@ -0,0 +1,54 @@
+package deep
+
+import "fmt"
+
+// NumericStage denotes where a non-finite value was produced
+type NumericStage int
+
+const (
+	// StageForward is a neuron value computed in a forward pass
+	StageForward NumericStage = 0
+	// StageGradient is a backpropagated gradient
+	StageGradient NumericStage = 1
+	// StageUpdate is a weight update returned by a solver
+	StageUpdate NumericStage = 2
+)
+
+func (s NumericStage) String() string {
+	switch s {
+	case StageForward:
+		return "forward pass"
+	case StageGradient:
+		return "gradient"
+	case StageUpdate:
+		return "weight update"
+	}
+	return "N/A"
+}
+
+// NumericError reports a NaN or infinite value and where it first occurred
+type NumericError struct {
+	Stage  NumericStage
+	Layer  int
+	Neuron int
+	// Synapse is the index of the neuron's input synapse, -1 for neuron values
+	Synapse int
+	Value   float64
+}
+
+func (e *NumericError) Error() string {
+	if e.Synapse < 0 {
+		return fmt.Sprintf("non-finite %s: %v at layer %d neuron %d", e.Stage, e.Value, e.Layer, e.Neuron)
+	}
+	return fmt.Sprintf("non-finite %s: %v at layer %d neuron %d synapse %d", e.Stage, e.Value, e.Layer, e.Neuron, e.Synapse)
+}
+
+// checkValues returns an error for the first neuron in l with a non-finite value
+func (l *Layer) checkValues(layer int) *NumericError {
+	for j, n := range l.Neurons {
+		if !IsFinite(n.Value) {
+			return &NumericError{Stage: StageForward, Layer: layer, Neuron: j, Synapse: -1, Value: n.Value}
+		}
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import "fmt"
 type Layer struct {
 	Neurons []*Neuron
 	A       ActivationType
+	// spans of a multi-head output layer, whose A is ActivationNone
+	spans []span
 }
 
 // NewLayer creates a new layer with n nodes
@@ -30,17 +32,35 @@ func (l *Layer) fire() {
 		n.fire()
 	}
 	if l.A == ActivationSoftmax {
-		outs := make([]float64, len(l.Neurons))
-		for i, neuron := range l.Neurons {
-			outs[i] = neuron.Value
-		}
-		sm := Softmax(outs)
-		for i, neuron := range l.Neurons {
-			neuron.Value = sm[i]
+		softmaxNeurons(l.Neurons)
+	}
+	for _, s := range l.spans {
+		if s.a == ActivationSoftmax {
+			softmaxNeurons(l.Neurons[s.start:s.end])
 		}
 	}
 }
 
+func softmaxNeurons(neurons []*Neuron) {
+	outs := make([]float64, len(neurons))
+	for i, neuron := range neurons {
+		outs[i] = neuron.Value
+	}
+	sm := Softmax(outs)
+	for i, neuron := range neurons {
+		neuron.Value = sm[i]
+	}
+}
+
+// values returns a copy of the neuron values
+func (l *Layer) values() []float64 {
+	values := make([]float64, len(l.Neurons))
+	for i, n := range l.Neurons {
+		values[i] = n.Value
+	}
+	return values
+}
+
 // Connect fully connects layer l to next, and initializes each
 // synapse with the given weight function
 func (l *Layer) Connect(next *Layer, weight WeightInitializer) {
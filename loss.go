@@ -1,20 +1,85 @@
 package deep
 
 import (
+	"cmp"
+	"encoding/json"
+	"fmt"
 	"math"
+	"sync"
 )
 
-// GetLoss returns a loss function given a LossType
+// GetLoss returns the loss function of a LossType with default options.
+// It panics if the loss is neither built in nor registered.
 func GetLoss(loss LossType) Loss {
+	l, err := NewLoss(loss, LossOptions{})
+	if err != nil {
+		panic("deep: " + err.Error())
+	}
+	return l
+}
+
+// LossOptions parameterize the built-in losses, zero values select the defaults
+type LossOptions struct {
+	// Smoothing mixes cross entropy targets with a uniform distribution,
+	// 0.1 by default for LossSmoothedCrossEntropy and 0 for LossCrossEntropy
+	Smoothing float64 `json:",omitempty"`
+	// Delta is the Huber threshold between squared and absolute error, 1 by default
+	Delta float64 `json:",omitempty"`
+	// Gamma is the focal loss focusing parameter, 2 by default
+	Gamma float64 `json:",omitempty"`
+}
+
+func (o LossOptions) validate(loss LossType) error {
+	if o.Smoothing < 0 || o.Smoothing >= 1 || !IsFinite(o.Smoothing) {
+		return fmt.Errorf("Invalid loss options - smoothing must be in [0, 1), got: %v", o.Smoothing)
+	}
+	if o.Delta < 0 || !IsFinite(o.Delta) {
+		return fmt.Errorf("Invalid loss options - delta must be positive, got: %v", o.Delta)
+	}
+	if o.Gamma < 0 || !IsFinite(o.Gamma) {
+		return fmt.Errorf("Invalid loss options - gamma must be positive, got: %v", o.Gamma)
+	}
+	if o.Smoothing != 0 && loss != LossCrossEntropy && loss != LossSmoothedCrossEntropy {
+		return fmt.Errorf("Invalid loss options - smoothing only applies to CE, got: %s", loss)
+	}
+	if o.Delta != 0 && loss != LossHuber {
+		return fmt.Errorf("Invalid loss options - delta only applies to Huber, got: %s", loss)
+	}
+	if o.Gamma != 0 && loss != LossFocal {
+		return fmt.Errorf("Invalid loss options - gamma only applies to Focal, got: %s", loss)
+	}
+	return nil
+}
+
+// NewLoss returns the loss function of a LossType configured by o
+func NewLoss(loss LossType, o LossOptions) (Loss, error) {
+	if err := o.validate(loss); err != nil {
+		return nil, err
+	}
 	switch loss {
 	case LossCrossEntropy:
-		return CrossEntropy{}
+		return CrossEntropy{Smoothing: o.Smoothing}, nil
 	case LossMeanSquared:
-		return MeanSquared{}
+		return MeanSquared{}, nil
 	case LossBinaryCrossEntropy:
-		return BinaryCrossEntropy{}
+		return BinaryCrossEntropy{}, nil
+	case LossHuber:
+		return Huber{Delta: cmp.Or(o.Delta, 1)}, nil
+	case LossMeanAbsolute:
+		return MeanAbsolute{}, nil
+	case LossHinge:
+		return Hinge{}, nil
+	case LossFocal:
+		return Focal{Gamma: cmp.Or(o.Gamma, 2)}, nil
+	case LossKLDivergence:
+		return KLDivergence{}, nil
+	case LossSmoothedCrossEntropy:
+		return CrossEntropy{Smoothing: cmp.Or(o.Smoothing, 0.1)}, nil
+	}
+	if r, ok := registry.get(loss); ok {
+		return r.loss, nil
 	}
-	return CrossEntropy{}
+	return nil, fmt.Errorf("Invalid loss - %d is not registered", loss)
 }
 
 // LossType represents a loss function
@@ -28,10 +93,126 @@ func (l LossType) String() string {
 		return "BinCE"
 	case LossMeanSquared:
 		return "MSE"
+	case LossHuber:
+		return "Huber"
+	case LossMeanAbsolute:
+		return "MAE"
+	case LossHinge:
+		return "Hinge"
+	case LossFocal:
+		return "Focal"
+	case LossKLDivergence:
+		return "KL"
+	case LossSmoothedCrossEntropy:
+		return "SmoothCE"
+	}
+	if r, ok := registry.get(l); ok {
+		return r.name
 	}
 	return "N/A"
 }
 
+// MarshalJSON encodes the loss by name, so that registered losses
+// are restored regardless of registration order
+func (l LossType) MarshalJSON() ([]byte, error) {
+	if l.String() == "N/A" {
+		return json.Marshal(int(l))
+	}
+	return json.Marshal(l.String())
+}
+
+// UnmarshalJSON decodes a loss name or number
+func (l *LossType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return json.Unmarshal(data, (*int)(l))
+	}
+	loss, err := ParseLoss(name)
+	if err != nil {
+		return err
+	}
+	*l = loss
+	return nil
+}
+
+// ParseLoss returns the LossType named name
+func ParseLoss(name string) (LossType, error) {
+	for l := LossCrossEntropy; l <= LossSmoothedCrossEntropy; l++ {
+		if l.String() == name {
+			return l, nil
+		}
+	}
+	if l, ok := registry.lookup(name); ok {
+		return l, nil
+	}
+	return LossNone, fmt.Errorf("unknown loss %q, custom losses must be registered with RegisterLoss", name)
+}
+
+// RegisterLoss makes loss selectable by the returned LossType and persisted
+// under name. It panics if the name is empty or already taken.
+func RegisterLoss(name string, loss Loss) LossType {
+	if name == "" || loss == nil {
+		panic("deep: RegisterLoss requires a name and a loss")
+	}
+	l, ok := registry.add(name, loss)
+	if !ok {
+		panic(fmt.Sprintf("deep: loss %q is already registered", name))
+	}
+	return l
+}
+
+const lossCustom LossType = 100
+
+type registeredLoss struct {
+	name string
+	loss Loss
+}
+
+type lossRegistry struct {
+	sync.RWMutex
+	losses []registeredLoss
+}
+
+var registry lossRegistry
+
+func (r *lossRegistry) get(l LossType) (registeredLoss, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	i := int(l - lossCustom)
+	if i < 0 || i >= len(r.losses) {
+		return registeredLoss{}, false
+	}
+	return r.losses[i], true
+}
+
+func (r *lossRegistry) lookup(name string) (LossType, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	for i, l := range r.losses {
+		if l.name == name {
+			return lossCustom + LossType(i), true
+		}
+	}
+	return LossNone, false
+}
+
+func (r *lossRegistry) add(name string, loss Loss) (LossType, bool) {
+	for l := LossCrossEntropy; l <= LossSmoothedCrossEntropy; l++ {
+		if l.String() == name {
+			return LossNone, false
+		}
+	}
+	r.Lock()
+	defer r.Unlock()
+	for _, l := range r.losses {
+		if l.name == name {
+			return LossNone, false
+		}
+	}
+	r.losses = append(r.losses, registeredLoss{name: name, loss: loss})
+	return lossCustom + LossType(len(r.losses)-1), true
+}
+
 const (
 	// LossNone signifies unspecified loss
 	LossNone LossType = 0
@@ -41,6 +222,18 @@ const (
 	LossBinaryCrossEntropy LossType = 2
 	// LossMeanSquared is MSE
 	LossMeanSquared LossType = 3
+	// LossHuber is Huber (smooth L1) loss with δ = 1
+	LossHuber LossType = 4
+	// LossMeanAbsolute is MAE
+	LossMeanAbsolute LossType = 5
+	// LossHinge is hinge loss
+	LossHinge LossType = 6
+	// LossFocal is focal loss with γ = 2
+	LossFocal LossType = 7
+	// LossKLDivergence is Kullback-Leibler divergence
+	LossKLDivergence LossType = 8
+	// LossSmoothedCrossEntropy is cross entropy with label smoothing of 0.1
+	LossSmoothedCrossEntropy LossType = 9
 )
 
 // Loss is satisfied by loss functions
@@ -49,8 +242,48 @@ type Loss interface {
 	Df(estimate, ideal, activation float64) float64
 }
 
-// CrossEntropy is CE loss
-type CrossEntropy struct{}
+// Canonical is implemented by losses whose Df is taken directly with respect to
+// the input sum of an output neuron with the given (canonical) activation, such as
+// cross entropy with softmax. Such losses are only valid with those activations.
+type Canonical interface {
+	Canonical(output ActivationType) bool
+}
+
+// Restricted is implemented by losses that are only defined for some output
+// activations, such as losses that expect probabilities
+type Restricted interface {
+	Supports(output ActivationType) bool
+}
+
+// Compatible reports whether loss may be used with the given output activation
+func Compatible(loss Loss, output ActivationType) bool {
+	if c, ok := loss.(Canonical); ok && !c.Canonical(output) {
+		return false
+	}
+	if r, ok := loss.(Restricted); ok && !r.Supports(output) {
+		return false
+	}
+	return true
+}
+
+// Deltas is implemented by losses whose derivative with respect to one output
+// depends on the others. Trainers use Deltas in place of Df when available.
+type Deltas interface {
+	Deltas(estimate, ideal, activation []float64) []float64
+}
+
+// Objective is implemented by losses whose Df is not the derivative of F
+// evaluated on a single example, but of a differently normalized objective
+type Objective interface {
+	// Objective is the per-example quantity differentiated by Df
+	Objective(estimate, ideal []float64) float64
+}
+
+// CrossEntropy is CE loss, optionally with label smoothing
+type CrossEntropy struct {
+	// Smoothing mixes the targets with a uniform distribution
+	Smoothing float64
+}
 
 // F is CE(...)
 func (l CrossEntropy) F(estimate, ideal [][]float64) float64 {
@@ -58,8 +291,9 @@ func (l CrossEntropy) F(estimate, ideal [][]float64) float64 {
 	var sum float64
 	for i := range estimate {
 		ce := 0.0
+		smoothed := l.smooth(ideal[i])
 		for j := range estimate[i] {
-			ce += ideal[i][j] * math.Log(estimate[i][j])
+			ce += smoothed[j] * math.Log(estimate[i][j])
 		}
 
 		sum -= ce
@@ -67,11 +301,37 @@ func (l CrossEntropy) F(estimate, ideal [][]float64) float64 {
 	return sum / float64(len(estimate))
 }
 
-// Df is CE'(...)
+// Df is CE'(...), not accounting for smoothing
 func (l CrossEntropy) Df(estimate, ideal, activation float64) float64 {
 	return estimate - ideal
 }
 
+// Deltas is CE'(...) with respect to the smoothed targets
+func (l CrossEntropy) Deltas(estimate, ideal, activation []float64) []float64 {
+	smoothed := l.smooth(ideal)
+	deltas := make([]float64, len(estimate))
+	for i := range deltas {
+		deltas[i] = estimate[i] - smoothed[i]
+	}
+	return deltas
+}
+
+func (l CrossEntropy) smooth(ideal []float64) []float64 {
+	if l.Smoothing == 0 {
+		return ideal
+	}
+	smoothed := make([]float64, len(ideal))
+	for i, y := range ideal {
+		smoothed[i] = (1-l.Smoothing)*y + l.Smoothing/float64(len(ideal))
+	}
+	return smoothed
+}
+
+// Canonical is true for softmax outputs
+func (l CrossEntropy) Canonical(output ActivationType) bool {
+	return output == ActivationSoftmax
+}
+
 // BinaryCrossEntropy is binary CE loss
 type BinaryCrossEntropy struct{}
 
@@ -94,6 +354,11 @@ func (l BinaryCrossEntropy) Df(estimate, ideal, activation float64) float64 {
 	return estimate - ideal
 }
 
+// Canonical is true for sigmoid outputs
+func (l BinaryCrossEntropy) Canonical(output ActivationType) bool {
+	return output == ActivationSigmoid
+}
+
 // MeanSquared in MSE loss
 type MeanSquared struct{}
 
@@ -112,3 +377,176 @@ func (l MeanSquared) F(estimate, ideal [][]float64) float64 {
 func (l MeanSquared) Df(estimate, ideal, activation float64) float64 {
 	return activation * (estimate - ideal)
 }
+
+// Objective is half the summed squared error, the quantity Df differentiates
+func (l MeanSquared) Objective(estimate, ideal []float64) float64 {
+	var sum float64
+	for i := range estimate {
+		sum += math.Pow(estimate[i]-ideal[i], 2)
+	}
+	return sum / 2
+}
+
+// Huber is Huber loss, quadratic for residuals within Delta and linear beyond
+type Huber struct {
+	Delta float64
+}
+
+// F is Huber(...)
+func (l Huber) F(estimate, ideal [][]float64) float64 {
+	var sum float64
+	for i := range estimate {
+		sum += l.Objective(estimate[i], ideal[i])
+	}
+	return sum / float64(len(estimate)*len(estimate[0]))
+}
+
+// Df is Huber'(...)
+func (l Huber) Df(estimate, ideal, activation float64) float64 {
+	return activation * math.Max(-l.Delta, math.Min(l.Delta, estimate-ideal))
+}
+
+// Objective is the summed Huber loss of an example
+func (l Huber) Objective(estimate, ideal []float64) float64 {
+	var sum float64
+	for i := range estimate {
+		r := math.Abs(estimate[i] - ideal[i])
+		if r <= l.Delta {
+			sum += r * r / 2
+		} else {
+			sum += l.Delta * (r - l.Delta/2)
+		}
+	}
+	return sum
+}
+
+// MeanAbsolute is MAE loss
+type MeanAbsolute struct{}
+
+// F is MAE(...)
+func (l MeanAbsolute) F(estimate, ideal [][]float64) float64 {
+	var sum float64
+	for i := range estimate {
+		sum += l.Objective(estimate[i], ideal[i])
+	}
+	return sum / float64(len(estimate)*len(estimate[0]))
+}
+
+// Df is MAE'(...)
+func (l MeanAbsolute) Df(estimate, ideal, activation float64) float64 {
+	return activation * Sgn(estimate-ideal)
+}
+
+// Objective is the summed absolute error of an example
+func (l MeanAbsolute) Objective(estimate, ideal []float64) float64 {
+	var sum float64
+	for i := range estimate {
+		sum += math.Abs(estimate[i] - ideal[i])
+	}
+	return sum
+}
+
+// Hinge is hinge loss, treating positive targets as +1 and others as -1
+type Hinge struct{}
+
+// F is Hinge(...)
+func (l Hinge) F(estimate, ideal [][]float64) float64 {
+	var sum float64
+	for i := range estimate {
+		sum += l.Objective(estimate[i], ideal[i])
+	}
+	return sum / float64(len(estimate)*len(estimate[0]))
+}
+
+// Df is Hinge'(...)
+func (l Hinge) Df(estimate, ideal, activation float64) float64 {
+	t := hingeTarget(ideal)
+	if t*estimate < 1 {
+		return -activation * t
+	}
+	return 0
+}
+
+// Objective is the summed hinge loss of an example
+func (l Hinge) Objective(estimate, ideal []float64) float64 {
+	var sum float64
+	for i := range estimate {
+		sum += math.Max(0, 1-hingeTarget(ideal[i])*estimate[i])
+	}
+	return sum
+}
+
+// Supports is true for unbounded outputs
+func (l Hinge) Supports(output ActivationType) bool {
+	return output != ActivationSoftmax && output != ActivationSigmoid
+}
+
+func hingeTarget(ideal float64) float64 {
+	if ideal > 0 {
+		return 1
+	}
+	return -1
+}
+
+// Focal is focal loss, which down-weights well classified examples by (1-p)^Gamma
+type Focal struct {
+	Gamma float64
+}
+
+// F is Focal(...)
+func (l Focal) F(estimate, ideal [][]float64) float64 {
+	const epsilon = 1e-16
+	var sum float64
+	for i := range estimate {
+		for j, p := range estimate[i] {
+			y := ideal[i][j]
+			sum -= y*math.Pow(1-p, l.Gamma)*math.Log(p+epsilon) +
+				(1-y)*math.Pow(p, l.Gamma)*math.Log(1-p+epsilon)
+		}
+	}
+	return sum / float64(len(estimate))
+}
+
+// Df is Focal'(...)
+func (l Focal) Df(estimate, ideal, activation float64) float64 {
+	const epsilon = 1e-16
+	p, y, g := estimate, ideal, l.Gamma
+	pos := y * math.Pow(1-p, g) / (p + epsilon)
+	neg := -(1 - y) * math.Pow(p, g) / (1 - p + epsilon)
+	if g != 0 {
+		pos -= y * g * math.Pow(1-p, g-1) * math.Log(p+epsilon)
+		neg += (1 - y) * g * math.Pow(p, g-1) * math.Log(1-p+epsilon)
+	}
+	return -activation * (pos + neg)
+}
+
+// Supports is true for probabilistic outputs
+func (l Focal) Supports(output ActivationType) bool {
+	return output == ActivationSoftmax || output == ActivationSigmoid
+}
+
+// KLDivergence is Kullback-Leibler divergence from soft targets
+type KLDivergence struct{}
+
+// F is KL(ideal || estimate)
+func (l KLDivergence) F(estimate, ideal [][]float64) float64 {
+	var sum float64
+	for i := range estimate {
+		for j, y := range ideal[i] {
+			if y > 0 {
+				sum += y * math.Log(y/estimate[i][j])
+			}
+		}
+	}
+	return sum / float64(len(estimate))
+}
+
+// Df is KL'(...)
+func (l KLDivergence) Df(estimate, ideal, activation float64) float64 {
+	return estimate - ideal
+}
+
+// Canonical is true for softmax outputs
+func (l KLDivergence) Canonical(output ActivationType) bool {
+	return output == ActivationSoftmax
+}
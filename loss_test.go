@@ -1,7 +1,9 @@
 package deep
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,6 +34,42 @@ func Test_Loss(t *testing.T) {
 			target: [][]float64{{0.5}},
 			res:    0.69,
 		},
+		{
+			loss:   LossHuber,
+			input:  [][]float64{{0.5, 1.0, 1.5}},
+			target: [][]float64{{0.0, 2.0, 2.0}},
+			res:    0.25,
+		},
+		{
+			loss:   LossMeanAbsolute,
+			input:  [][]float64{{0.5, 1.0, 1.5}},
+			target: [][]float64{{0.0, 2.0, 2.0}},
+			res:    0.67,
+		},
+		{
+			loss:   LossHinge,
+			input:  [][]float64{{0.5, -2.0, 1.5}},
+			target: [][]float64{{1.0, 0.0, 1.0}},
+			res:    0.17,
+		},
+		{
+			loss:   LossFocal,
+			input:  [][]float64{{0.5}},
+			target: [][]float64{{1.0}},
+			res:    0.17,
+		},
+		{
+			loss:   LossKLDivergence,
+			input:  [][]float64{{0.25, 0.75}},
+			target: [][]float64{{0.5, 0.5}},
+			res:    0.14,
+		},
+		{
+			loss:   LossSmoothedCrossEntropy,
+			input:  [][]float64{{0.5, 0.5}},
+			target: [][]float64{{1.0, 0.0}},
+			res:    0.69,
+		},
 	}
 	for _, test := range tests {
 		loss := GetLoss(test.loss)
@@ -40,3 +78,97 @@ func Test_Loss(t *testing.T) {
 		assert.NotEqual(t, "N/A", test.loss.String())
 	}
 }
+
+func Test_LabelSmoothing(t *testing.T) {
+	ideal := []float64{0, 1, 0, 0}
+	deltas := CrossEntropy{Smoothing: 0.2}.Deltas([]float64{0.1, 0.7, 0.1, 0.1}, ideal, nil)
+
+	assert.InDeltaSlice(t, []float64{0.05, -0.15, 0.05, 0.05}, deltas, 1e-12)
+	assert.Equal(t, []float64{0, 1, 0, 0}, ideal)
+}
+
+func Test_NewLoss(t *testing.T) {
+	loss, err := NewLoss(LossCrossEntropy, LossOptions{Smoothing: 0.2})
+	assert.Nil(t, err)
+	assert.Equal(t, CrossEntropy{Smoothing: 0.2}, loss)
+
+	loss, err = NewLoss(LossSmoothedCrossEntropy, LossOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, CrossEntropy{Smoothing: 0.1}, loss)
+
+	loss, err = NewLoss(LossHuber, LossOptions{Delta: 0.5})
+	assert.Nil(t, err)
+	assert.Equal(t, Huber{Delta: 0.5}, loss)
+
+	loss, err = NewLoss(LossFocal, LossOptions{Gamma: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, Focal{Gamma: 1}, loss)
+
+	tests := []struct {
+		loss    LossType
+		options LossOptions
+		err     string
+	}{
+		{LossCrossEntropy, LossOptions{Smoothing: 1}, "Invalid loss options - smoothing must be in [0, 1), got: 1"},
+		{LossHuber, LossOptions{Delta: -1}, "Invalid loss options - delta must be positive, got: -1"},
+		{LossFocal, LossOptions{Gamma: math.NaN()}, "Invalid loss options - gamma must be positive, got: NaN"},
+		{LossMeanSquared, LossOptions{Smoothing: 0.1}, "Invalid loss options - smoothing only applies to CE, got: MSE"},
+		{LossFocal, LossOptions{Delta: 2}, "Invalid loss options - delta only applies to Huber, got: Focal"},
+		{lossScaledSquared, LossOptions{Gamma: 2}, "Invalid loss options - gamma only applies to Focal, got: scaled-mse"},
+		{LossNone, LossOptions{}, "Invalid loss - 0 is not registered"},
+		{42, LossOptions{}, "Invalid loss - 42 is not registered"},
+	}
+	for _, test := range tests {
+		loss, err := NewLoss(test.loss, test.options)
+		assert.Nil(t, loss)
+		assert.EqualError(t, err, test.err)
+	}
+	assert.PanicsWithValue(t, "deep: Invalid loss - 42 is not registered", func() { GetLoss(42) })
+}
+
+func Test_Compatible(t *testing.T) {
+	assert.True(t, Compatible(CrossEntropy{}, ActivationSoftmax))
+	assert.False(t, Compatible(CrossEntropy{}, ActivationTanh))
+	assert.True(t, Compatible(Focal{}, ActivationSigmoid))
+	assert.False(t, Compatible(Focal{}, ActivationLinear))
+	assert.True(t, Compatible(MeanSquared{}, ActivationSoftmax))
+}
+
+type scaledSquared struct{ MeanSquared }
+
+// registered once per test binary, as registering a name twice panics
+var lossScaledSquared = RegisterLoss("scaled-mse", scaledSquared{})
+
+func (l scaledSquared) Df(estimate, ideal, activation float64) float64 {
+	return 2 * l.MeanSquared.Df(estimate, ideal, activation)
+}
+
+func Test_RegisterLoss(t *testing.T) {
+	loss := lossScaledSquared
+
+	assert.Equal(t, "scaled-mse", loss.String())
+	assert.Equal(t, scaledSquared{}, GetLoss(loss))
+
+	parsed, err := ParseLoss("scaled-mse")
+	assert.Nil(t, err)
+	assert.Equal(t, loss, parsed)
+
+	_, err = ParseLoss("unregistered")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { RegisterLoss("scaled-mse", MeanSquared{}) })
+	assert.Panics(t, func() { RegisterLoss("MSE", MeanSquared{}) })
+}
+
+func Test_LossJSON(t *testing.T) {
+	data, err := json.Marshal(LossHuber)
+	assert.Nil(t, err)
+	assert.Equal(t, `"Huber"`, string(data))
+
+	var loss LossType
+	assert.Nil(t, json.Unmarshal([]byte(`"KL"`), &loss))
+	assert.Equal(t, LossKLDivergence, loss)
+	assert.Nil(t, json.Unmarshal([]byte(`3`), &loss))
+	assert.Equal(t, LossMeanSquared, loss)
+	assert.Error(t, json.Unmarshal([]byte(`"unregistered"`), &loss))
+}
@@ -0,0 +1,350 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	deep "github.com/patrikeh/go-deep"
+)
+
+// Confusion is a confusion matrix, Counts[actual][predicted] counts the
+// examples of the actual class predicted as the predicted class
+type Confusion struct {
+	Counts [][]int
+}
+
+// Confusion returns the confusion matrix of the results. The class of an
+// output vector is its argmax, single binary outputs are thresholded at 0.5.
+func (r Results) Confusion() Confusion {
+	classes := 2
+	if len(r.Actual) > 0 {
+		classes = max(len(r.Actual[0]), 2)
+	}
+	c := Confusion{Counts: make([][]int, classes)}
+	for i := range c.Counts {
+		c.Counts[i] = make([]int, classes)
+	}
+	for i := range r.Predicted {
+		c.Counts[class(r.Actual[i])][class(r.Predicted[i])]++
+	}
+	return c
+}
+
+// Classes is the number of classes
+func (c Confusion) Classes() int {
+	return len(c.Counts)
+}
+
+// Accuracy is the fraction of correctly classified examples
+func (c Confusion) Accuracy() float64 {
+	var correct, total int
+	for i := range c.Counts {
+		for j, n := range c.Counts[i] {
+			if i == j {
+				correct += n
+			}
+			total += n
+		}
+	}
+	return ratio(correct, total)
+}
+
+// Precision of class, 0 if it was never predicted
+func (c Confusion) Precision(class int) float64 {
+	tp, fp, _ := c.counts(class)
+	return ratio(tp, tp+fp)
+}
+
+// Recall of class, 0 if it never occurs
+func (c Confusion) Recall(class int) float64 {
+	tp, _, fn := c.counts(class)
+	return ratio(tp, tp+fn)
+}
+
+// F1 is the harmonic mean of the precision and recall of class
+func (c Confusion) F1(class int) float64 {
+	return f1(c.Precision(class), c.Recall(class))
+}
+
+// MacroPrecision is the unweighted mean of the per-class precisions
+func (c Confusion) MacroPrecision() float64 {
+	return c.macro(c.Precision)
+}
+
+// MacroRecall is the unweighted mean of the per-class recalls
+func (c Confusion) MacroRecall() float64 {
+	return c.macro(c.Recall)
+}
+
+// MacroF1 is the unweighted mean of the per-class F1 scores
+func (c Confusion) MacroF1() float64 {
+	return c.macro(c.F1)
+}
+
+// MicroPrecision is the precision of true and false positives summed over classes
+func (c Confusion) MicroPrecision() float64 {
+	tp, fp, _ := c.micro()
+	return ratio(tp, tp+fp)
+}
+
+// MicroRecall is the recall of true positives and false negatives summed over classes
+func (c Confusion) MicroRecall() float64 {
+	tp, _, fn := c.micro()
+	return ratio(tp, tp+fn)
+}
+
+// MicroF1 is the harmonic mean of the micro precision and recall
+func (c Confusion) MicroF1() float64 {
+	return f1(c.MicroPrecision(), c.MicroRecall())
+}
+
+func (c Confusion) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "actual\\predicted\t")
+	for j := range c.Counts {
+		fmt.Fprintf(w, "%d\t", j)
+	}
+	fmt.Fprintln(w, "recall\t")
+	for i, row := range c.Counts {
+		fmt.Fprintf(w, "%d\t", i)
+		for _, n := range row {
+			fmt.Fprintf(w, "%d\t", n)
+		}
+		fmt.Fprintf(w, "%.3f\t\n", c.Recall(i))
+	}
+	fmt.Fprint(w, "precision\t")
+	for j := range c.Counts {
+		fmt.Fprintf(w, "%.3f\t", c.Precision(j))
+	}
+	fmt.Fprintf(w, "%.3f\t\n", c.Accuracy())
+	w.Flush()
+	return b.String()
+}
+
+// counts returns the true positives, false positives and false negatives of class
+func (c Confusion) counts(class int) (tp, fp, fn int) {
+	for i := range c.Counts {
+		switch {
+		case i == class:
+			tp += c.Counts[i][i]
+		default:
+			fp += c.Counts[i][class]
+			fn += c.Counts[class][i]
+		}
+	}
+	return tp, fp, fn
+}
+
+func (c Confusion) micro() (tp, fp, fn int) {
+	for class := range c.Counts {
+		t, f, n := c.counts(class)
+		tp, fp, fn = tp+t, fp+f, fn+n
+	}
+	return tp, fp, fn
+}
+
+func (c Confusion) macro(metric func(class int) float64) float64 {
+	var sum float64
+	for class := range c.Counts {
+		sum += metric(class)
+	}
+	return sum / float64(len(c.Counts))
+}
+
+// Accuracy is the fraction of correctly classified examples
+func (r Results) Accuracy() float64 {
+	return r.Confusion().Accuracy()
+}
+
+// TopK is the fraction of examples whose actual class is among the k highest
+// predicted outputs. Single binary outputs p are ranked as the two classes {1-p, p}.
+func (r Results) TopK(k int) float64 {
+	var correct int
+	for i := range r.Predicted {
+		actual, predicted := class(r.Actual[i]), r.Predicted[i]
+		if len(predicted) == 1 {
+			predicted = []float64{1 - predicted[0], predicted[0]}
+		}
+		var higher int
+		for _, p := range predicted {
+			if p > predicted[actual] {
+				higher++
+			}
+		}
+		if higher < k {
+			correct++
+		}
+	}
+	return ratio(correct, len(r.Predicted))
+}
+
+// LogLoss is the mean cross entropy of the predicted probabilities, binary
+// cross entropy for single outputs. Probabilities are clipped to avoid log(0).
+func (r Results) LogLoss() float64 {
+	const eps = 1e-15
+	var sum float64
+	for i := range r.Predicted {
+		if len(r.Predicted[i]) == 1 {
+			p, y := math.Min(math.Max(r.Predicted[i][0], eps), 1-eps), r.Actual[i][0]
+			sum -= y*math.Log(p) + (1-y)*math.Log(1-p)
+			continue
+		}
+		for j, p := range r.Predicted[i] {
+			sum -= r.Actual[i][j] * math.Log(math.Max(p, eps))
+		}
+	}
+	return sum / float64(len(r.Predicted))
+}
+
+// HammingLoss is the fraction of multi-label outputs, thresholded at 0.5, that are wrong
+func (r Results) HammingLoss() float64 {
+	var wrong, total int
+	for i := range r.Predicted {
+		for j, p := range r.Predicted[i] {
+			if (p >= 0.5) != (r.Actual[i][j] >= 0.5) {
+				wrong++
+			}
+			total++
+		}
+	}
+	return ratio(wrong, total)
+}
+
+// ROCAUC is the area under the ROC curve of class against all others, i.e. the
+// probability that a positive example scores higher than a negative one.
+// It is NaN if the class has no positive or no negative examples.
+func (r Results) ROCAUC(class int) float64 {
+	scores, positive := r.scores(class)
+	order := ranked(scores)
+
+	var positives, rankSum float64
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && scores[order[end]] == scores[order[start]] {
+			end++
+		}
+		// ties share the average of their ranks
+		rank := float64(start+end+1) / 2
+		for _, i := range order[start:end] {
+			if positive[i] {
+				positives++
+				rankSum += rank
+			}
+		}
+		start = end
+	}
+	negatives := float64(len(scores)) - positives
+	if positives == 0 || negatives == 0 {
+		return math.NaN()
+	}
+	return (rankSum - positives*(positives+1)/2) / (positives * negatives)
+}
+
+// MacroROCAUC is the mean ROCAUC over classes, skipping classes where it is undefined
+func (r Results) MacroROCAUC() float64 {
+	var sum float64
+	var count int
+	for class := 0; class < r.Confusion().Classes(); class++ {
+		if auc := r.ROCAUC(class); !math.IsNaN(auc) {
+			sum += auc
+			count++
+		}
+	}
+	if count == 0 {
+		return math.NaN()
+	}
+	return sum / float64(count)
+}
+
+// PRAUC is the area under the precision-recall curve of class, computed as
+// average precision. It is NaN if the class has no positive examples.
+func (r Results) PRAUC(class int) float64 {
+	scores, positive := r.scores(class)
+	order := ranked(scores)
+
+	var positives int
+	for _, p := range positive {
+		if p {
+			positives++
+		}
+	}
+	if positives == 0 {
+		return math.NaN()
+	}
+
+	var tp, fp int
+	var area, recall float64
+	for end := len(order); end > 0; {
+		start := end - 1
+		for start > 0 && scores[order[start-1]] == scores[order[end-1]] {
+			start--
+		}
+		for _, i := range order[start:end] {
+			if positive[i] {
+				tp++
+			} else {
+				fp++
+			}
+		}
+		next := ratio(tp, positives)
+		area += (next - recall) * ratio(tp, tp+fp)
+		recall, end = next, start
+	}
+	return area
+}
+
+// scores returns the predicted score of class for each example and whether it is a positive.
+// Single binary outputs score class 0 as 1-p.
+func (r Results) scores(class int) ([]float64, []bool) {
+	scores, positive := make([]float64, len(r.Predicted)), make([]bool, len(r.Predicted))
+	for i := range r.Predicted {
+		if len(r.Predicted[i]) == 1 {
+			scores[i], positive[i] = r.Predicted[i][0], r.Actual[i][0] >= 0.5
+			if class == 0 {
+				scores[i], positive[i] = 1-scores[i], !positive[i]
+			}
+			continue
+		}
+		scores[i], positive[i] = r.Predicted[i][class], r.Actual[i][class] >= 0.5
+	}
+	return scores, positive
+}
+
+// ranked returns the indices of scores in ascending order
+func ranked(scores []float64) []int {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] < scores[order[b]] })
+	return order
+}
+
+// class is the argmax of a target or prediction, or 0/1 for a single binary output
+func class(output []float64) int {
+	if len(output) == 1 {
+		if output[0] >= 0.5 {
+			return 1
+		}
+		return 0
+	}
+	return deep.ArgMax(output)
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+func f1(precision, recall float64) float64 {
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var multiclass = Results{
+	Predicted: [][]float64{
+		{0.7, 0.2, 0.1},
+		{0.1, 0.8, 0.1},
+		{0.3, 0.4, 0.3},
+		{0.2, 0.2, 0.6},
+		{0.5, 0.1, 0.4},
+	},
+	Actual: [][]float64{
+		{1, 0, 0},
+		{0, 1, 0},
+		{1, 0, 0},
+		{0, 0, 1},
+		{0, 0, 1},
+	},
+}
+
+func Test_Confusion(t *testing.T) {
+	c := multiclass.Confusion()
+	assert.Equal(t, [][]int{{1, 1, 0}, {0, 1, 0}, {1, 0, 1}}, c.Counts)
+	assert.Equal(t, 3, c.Classes())
+	assert.InDelta(t, 0.6, c.Accuracy(), 1e-9)
+
+	assert.InDelta(t, 0.5, c.Precision(0), 1e-9)
+	assert.InDelta(t, 0.5, c.Recall(0), 1e-9)
+	assert.InDelta(t, 0.5, c.Precision(1), 1e-9)
+	assert.InDelta(t, 1.0, c.Recall(1), 1e-9)
+	assert.InDelta(t, 2.0/3, c.F1(1), 1e-9)
+	assert.InDelta(t, 1.0, c.Precision(2), 1e-9)
+	assert.InDelta(t, 0.5, c.Recall(2), 1e-9)
+
+	assert.InDelta(t, (0.5+0.5+1)/3, c.MacroPrecision(), 1e-9)
+	assert.InDelta(t, (0.5+1+0.5)/3, c.MacroRecall(), 1e-9)
+	assert.InDelta(t, (0.5+2.0/3+2.0/3)/3, c.MacroF1(), 1e-9)
+	// single-label micro averages equal accuracy
+	assert.InDelta(t, 0.6, c.MicroPrecision(), 1e-9)
+	assert.InDelta(t, 0.6, c.MicroRecall(), 1e-9)
+	assert.InDelta(t, 0.6, c.MicroF1(), 1e-9)
+	assert.Contains(t, c.String(), "precision")
+}
+
+func Test_BinaryConfusion(t *testing.T) {
+	r := Results{
+		Predicted: [][]float64{{0.9}, {0.4}, {0.6}, {0.1}},
+		Actual:    [][]float64{{1}, {1}, {0}, {0}},
+	}
+	c := r.Confusion()
+	assert.Equal(t, [][]int{{1, 1}, {1, 1}}, c.Counts)
+	assert.InDelta(t, 0.5, r.Accuracy(), 1e-9)
+	assert.InDelta(t, 0.75, r.ROCAUC(1), 1e-9)
+	assert.InDelta(t, 0.75, r.ROCAUC(0), 1e-9)
+}
+
+func Test_TopK(t *testing.T) {
+	assert.InDelta(t, 0.6, multiclass.TopK(1), 1e-9)
+	assert.InDelta(t, 1.0, multiclass.TopK(2), 1e-9)
+	assert.InDelta(t, 0.5, Results{
+		Predicted: [][]float64{{0.1, 0.2, 0.7}, {0.1, 0.2, 0.7}},
+		Actual:    [][]float64{{1, 0, 0}, {0, 1, 0}},
+	}.TopK(2), 1e-9)
+
+	binary := Results{
+		Predicted: [][]float64{{0.9}, {0.4}, {0.6}, {0.1}},
+		Actual:    [][]float64{{1}, {1}, {0}, {0}},
+	}
+	assert.InDelta(t, 0.5, binary.TopK(1), 1e-9)
+	assert.InDelta(t, 1.0, binary.TopK(2), 1e-9)
+}
+
+func Test_LogLoss(t *testing.T) {
+	expected := -(math.Log(0.7) + math.Log(0.8) + math.Log(0.3) + math.Log(0.6) + math.Log(0.4)) / 5
+	assert.InDelta(t, expected, multiclass.LogLoss(), 1e-9)
+
+	binary := Results{Predicted: [][]float64{{0.8}, {0.3}}, Actual: [][]float64{{1}, {0}}}
+	assert.InDelta(t, -(math.Log(0.8)+math.Log(0.7))/2, binary.LogLoss(), 1e-9)
+
+	certain := Results{Predicted: [][]float64{{0}}, Actual: [][]float64{{1}}}
+	assert.False(t, math.IsInf(certain.LogLoss(), 0))
+}
+
+func Test_HammingLoss(t *testing.T) {
+	r := Results{
+		Predicted: [][]float64{{0.9, 0.2, 0.7}, {0.1, 0.6, 0.4}},
+		Actual:    [][]float64{{1, 0, 0}, {0, 1, 1}},
+	}
+	assert.InDelta(t, 2.0/6, r.HammingLoss(), 1e-9)
+}
+
+func Test_ROCAUC(t *testing.T) {
+	r := Results{
+		Predicted: [][]float64{{0.1}, {0.4}, {0.35}, {0.8}},
+		Actual:    [][]float64{{0}, {0}, {1}, {1}},
+	}
+	assert.InDelta(t, 0.75, r.ROCAUC(1), 1e-9)
+	assert.InDelta(t, 1.0, Results{Predicted: [][]float64{{0.2}, {0.9}}, Actual: [][]float64{{0}, {1}}}.ROCAUC(1), 1e-9)
+
+	ties := Results{Predicted: [][]float64{{0.5}, {0.5}}, Actual: [][]float64{{0}, {1}}}
+	assert.InDelta(t, 0.5, ties.ROCAUC(1), 1e-9)
+
+	assert.True(t, math.IsNaN(Results{Predicted: [][]float64{{0.5}}, Actual: [][]float64{{1}}}.ROCAUC(1)))
+
+	// class 1 scores 0.8 and 0.4 for its positive, 0.2, 0.1, 0.2 for negatives
+	assert.InDelta(t, 1.0, multiclass.ROCAUC(1), 1e-9)
+	assert.False(t, math.IsNaN(multiclass.MacroROCAUC()))
+}
+
+func Test_PRAUC(t *testing.T) {
+	r := Results{
+		Predicted: [][]float64{{0.1}, {0.4}, {0.35}, {0.8}},
+		Actual:    [][]float64{{0}, {0}, {1}, {1}},
+	}
+	// ranked: 0.8 (+), 0.4 (-), 0.35 (+), 0.1 (-)
+	assert.InDelta(t, 0.5*1+0.5*2.0/3, r.PRAUC(1), 1e-9)
+
+	perfect := Results{Predicted: [][]float64{{0.2}, {0.9}}, Actual: [][]float64{{0}, {1}}}
+	assert.InDelta(t, 1.0, perfect.PRAUC(1), 1e-9)
+	assert.True(t, math.IsNaN(Results{Predicted: [][]float64{{0.5}}, Actual: [][]float64{{0}}}.PRAUC(1)))
+}
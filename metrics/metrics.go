@@ -0,0 +1,38 @@
+// Package metrics evaluates trained networks on labelled data
+package metrics
+
+import (
+	deep "github.com/patrikeh/go-deep"
+)
+
+// Dataset is a set of inputs and their targets, such as training.Examples
+type Dataset interface {
+	Inputs() [][]float64
+	Responses() [][]float64
+}
+
+// Results are predictions paired with their targets. They are obtained
+// from Evaluate, or built directly from raw predictions.
+type Results struct {
+	Predicted [][]float64
+	Actual    [][]float64
+}
+
+// Evaluate predicts each input of data with p, which can be a *deep.Neural or an ensemble
+func Evaluate(p deep.Predictor, data Dataset) Results {
+	inputs := data.Inputs()
+	r := Results{Predicted: make([][]float64, len(inputs)), Actual: data.Responses()}
+	for i, input := range inputs {
+		r.Predicted[i] = p.Predict(input)
+	}
+	return r
+}
+
+// Outputs returns the results restricted to outputs start to end, such as one head of a network
+func (r Results) Outputs(start, end int) Results {
+	s := Results{Predicted: make([][]float64, len(r.Predicted)), Actual: make([][]float64, len(r.Actual))}
+	for i := range r.Predicted {
+		s.Predicted[i], s.Actual[i] = r.Predicted[i][start:end], r.Actual[i][start:end]
+	}
+	return s
+}
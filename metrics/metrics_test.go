@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"math/rand"
+	"testing"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/stretchr/testify/assert"
+)
+
+type dataset struct {
+	inputs, responses [][]float64
+}
+
+func (d dataset) Inputs() [][]float64    { return d.inputs }
+func (d dataset) Responses() [][]float64 { return d.responses }
+
+func Test_Evaluate(t *testing.T) {
+	rand.Seed(0)
+	n := deep.NewNeural(&deep.Config{
+		Inputs: 2,
+		Layout: []int{3, 3},
+		Mode:   deep.ModeMultiClass,
+		Bias:   true,
+	})
+	data := dataset{
+		inputs:    [][]float64{{0, 1}, {1, 0}},
+		responses: [][]float64{{1, 0, 0, 7}, {0, 1, 0, 8}},
+	}
+
+	r := Evaluate(n, data)
+	assert.Equal(t, n.Predict(data.inputs[1]), r.Predicted[1])
+	assert.Equal(t, data.responses, r.Actual)
+
+	head := r.Outputs(0, 3)
+	assert.Equal(t, [][]float64{{1, 0, 0}, {0, 1, 0}}, head.Actual)
+	assert.Equal(t, r.Predicted, head.Predicted)
+}
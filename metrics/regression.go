@@ -0,0 +1,59 @@
+package metrics
+
+import "math"
+
+// MAE is the mean absolute error over all outputs
+func (r Results) MAE() float64 {
+	var sum float64
+	var count int
+	for i := range r.Predicted {
+		for j, p := range r.Predicted[i] {
+			sum += math.Abs(p - r.Actual[i][j])
+			count++
+		}
+	}
+	return sum / float64(count)
+}
+
+// RMSE is the root mean squared error over all outputs
+func (r Results) RMSE() float64 {
+	var sum float64
+	var count int
+	for i := range r.Predicted {
+		for j, p := range r.Predicted[i] {
+			sum += math.Pow(p-r.Actual[i][j], 2)
+			count++
+		}
+	}
+	return math.Sqrt(sum / float64(count))
+}
+
+// R2 is the coefficient of determination, averaged over outputs.
+// An output with constant targets scores 1 if predicted exactly, otherwise 0.
+func (r Results) R2() float64 {
+	if len(r.Actual) == 0 {
+		return 0
+	}
+	outputs := len(r.Actual[0])
+	var sum float64
+	for j := 0; j < outputs; j++ {
+		var mean float64
+		for i := range r.Actual {
+			mean += r.Actual[i][j]
+		}
+		mean /= float64(len(r.Actual))
+
+		var residual, total float64
+		for i := range r.Actual {
+			residual += math.Pow(r.Actual[i][j]-r.Predicted[i][j], 2)
+			total += math.Pow(r.Actual[i][j]-mean, 2)
+		}
+		switch {
+		case total > 0:
+			sum += 1 - residual/total
+		case residual == 0:
+			sum++
+		}
+	}
+	return sum / float64(outputs)
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Regression(t *testing.T) {
+	r := Results{
+		Predicted: [][]float64{{2.5}, {0}, {2}, {8}},
+		Actual:    [][]float64{{3}, {-0.5}, {2}, {7}},
+	}
+	assert.InDelta(t, 0.5, r.MAE(), 1e-9)
+	assert.InDelta(t, math.Sqrt(0.375), r.RMSE(), 1e-9)
+	assert.InDelta(t, 0.9486081370449679, r.R2(), 1e-9)
+
+	constant := Results{Predicted: [][]float64{{1, 1}, {1, 2}}, Actual: [][]float64{{1, 1}, {1, 1}}}
+	assert.InDelta(t, 0.5, constant.R2(), 1e-9)
+}
@@ -26,13 +26,106 @@ type Config struct {
 	Mode Mode
 	// Initializer for weights: {NewNormal(σ, μ), NewUniform(σ, μ)}
 	Weight WeightInitializer `json:"-"`
-	// Loss functions: {LossCrossEntropy, LossBinaryCrossEntropy, LossMeanSquared}
+	// Loss functions: {LossCrossEntropy, LossBinaryCrossEntropy, LossMeanSquared, LossHuber,
+	// LossMeanAbsolute, LossHinge, LossFocal, LossKLDivergence, LossSmoothedCrossEntropy}
 	Loss LossType
+	// LossOptions parameterize the loss, such as CE label smoothing or the Huber delta
+	LossOptions LossOptions
 	// Apply bias nodes
 	Bias bool
+	// Loss weight per class in Binary and MultiClass mode, indexed by the target's
+	// argmax. Single binary outputs are weighted by the negative and positive class.
+	ClassWeights []float64 `json:",omitempty"`
+	// Heads splits the output layer into consecutive heads, each with its own
+	// mode and loss. Mode, Loss, LossOptions and ClassWeights must be unset when used.
+	Heads []Head `json:",omitempty"`
+	// InputScaler, if set, is applied to inputs before the forward pass
+	InputScaler *Scaler `json:",omitempty"`
+	// TargetScaler, if set, is applied to regression targets in training
+	// and inverted on predictions
+	TargetScaler *Scaler `json:",omitempty"`
 }
 
-// NewNeural returns a new neural network
+// Validate checks that the config describes a trainable network: a non-empty
+// layout, a known mode and activation, a registered loss that is compatible
+// with the output activation, one class weight per class and scalers
+// matching the inputs and regression outputs.
+// Unset activation and loss are validated as their defaults.
+func (c *Config) Validate() error {
+	if c.Inputs <= 0 {
+		return fmt.Errorf("Invalid inputs - expected positive, got: %d", c.Inputs)
+	}
+	if len(c.Layout) == 0 {
+		return fmt.Errorf("Invalid layout - expected at least one layer")
+	}
+	for i, size := range c.Layout {
+		if size <= 0 {
+			return fmt.Errorf("Invalid layout - layer %d has %d neurons", i, size)
+		}
+	}
+	if c.Mode < ModeDefault || c.Mode > ModeMultiLabel {
+		return fmt.Errorf("Invalid mode: %d", c.Mode)
+	}
+	if c.Activation < ActivationNone || c.Activation > ActivationSoftmax {
+		return fmt.Errorf("Invalid activation: %d", c.Activation)
+	}
+	if c.Activation == ActivationSoftmax && len(c.Layout) > 1 {
+		return fmt.Errorf("Invalid activation - softmax is only supported in the output layer")
+	}
+
+	outputs := c.Layout[len(c.Layout)-1]
+	if len(c.Heads) > 0 {
+		if err := c.validateHeads(outputs); err != nil {
+			return err
+		}
+	} else if err := c.validateHead(Head{Outputs: outputs, Mode: c.Mode, Loss: c.Loss, LossOptions: c.LossOptions}); err != nil {
+		return err
+	}
+
+	if c.ClassWeights != nil {
+		if c.Mode != ModeBinary && c.Mode != ModeMultiClass {
+			return fmt.Errorf("Invalid class weights - expected Binary or MultiClass mode, got: %s", c.Mode)
+		}
+		if classes := max(outputs, 2); len(c.ClassWeights) != classes {
+			return fmt.Errorf("Invalid class weights - expected: %d got: %d", classes, len(c.ClassWeights))
+		}
+		for i, w := range c.ClassWeights {
+			if w < 0 || !IsFinite(w) {
+				return fmt.Errorf("Invalid class weights - class %d has weight %v", i, w)
+			}
+		}
+	}
+
+	if c.InputScaler != nil {
+		if err := c.InputScaler.validate("input", c.Inputs); err != nil {
+			return err
+		}
+	}
+	if c.TargetScaler != nil {
+		for _, h := range c.OutputHeads() {
+			if h.Mode != ModeRegression {
+				return fmt.Errorf("Invalid target scaler - expected regression outputs, got: %s", h.Mode)
+			}
+		}
+		if err := c.TargetScaler.validate("target", outputs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func defaultLoss(mode Mode) LossType {
+	switch mode {
+	case ModeMultiClass:
+		return LossCrossEntropy
+	case ModeBinary, ModeMultiLabel:
+		return LossBinaryCrossEntropy
+	}
+	return LossMeanSquared
+}
+
+// NewNeural returns a new neural network. The config is not validated,
+// see NewNeuralE.
 func NewNeural(c *Config) *Neural {
 
 	if c.Weight == nil {
@@ -41,14 +134,12 @@ func NewNeural(c *Config) *Neural {
 	if c.Activation == ActivationNone {
 		c.Activation = ActivationSigmoid
 	}
-	if c.Loss == LossNone {
-		switch c.Mode {
-		case ModeMultiClass, ModeMultiLabel:
-			c.Loss = LossCrossEntropy
-		case ModeBinary:
-			c.Loss = LossBinaryCrossEntropy
-		default:
-			c.Loss = LossMeanSquared
+	if c.Loss == LossNone && len(c.Heads) == 0 {
+		c.Loss = defaultLoss(c.Mode)
+	}
+	for i := range c.Heads {
+		if c.Heads[i].Loss == LossNone {
+			c.Heads[i].Loss = defaultLoss(c.Heads[i].Mode)
 		}
 	}
 
@@ -58,7 +149,7 @@ func NewNeural(c *Config) *Neural {
 	if c.Bias {
 		biases = make([][]*Synapse, len(layers))
 		for i := 0; i < len(layers); i++ {
-			if c.Mode == ModeRegression && i == len(layers)-1 {
+			if !c.hasBias(i) {
 				continue
 			}
 			biases[i] = layers[i].ApplyBias(c.Weight)
@@ -72,6 +163,19 @@ func NewNeural(c *Config) *Neural {
 	}
 }
 
+// NewNeuralE returns a new neural network, or an error if c is invalid
+func NewNeuralE(c *Config) (*Neural, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return NewNeural(c), nil
+}
+
+// hasBias reports whether layer i has a bias synapse, regression outputs have none
+func (c *Config) hasBias(i int) bool {
+	return c.Bias && !(c.Mode == ModeRegression && len(c.Heads) == 0 && i == len(c.Layout)-1)
+}
+
 func initializeLayers(c *Config) []*Layer {
 	layers := make([]*Layer, len(c.Layout))
 	for i := range layers {
@@ -81,6 +185,9 @@ func initializeLayers(c *Config) []*Layer {
 		}
 		layers[i] = NewLayer(c.Layout[i], act)
 	}
+	if len(c.Heads) > 0 {
+		layers[len(layers)-1] = newHeadLayer(c)
+	}
 
 	for i := 0; i < len(layers)-1; i++ {
 		layers[i].Connect(layers[i+1], c.Weight)
@@ -96,41 +203,58 @@ func initializeLayers(c *Config) []*Layer {
 	return layers
 }
 
-func (n *Neural) fire() {
+func (n *Neural) fire() error {
 	for _, b := range n.Biases {
 		for _, s := range b {
 			s.fire(1)
 		}
 	}
-	for _, l := range n.Layers {
+	var err *NumericError
+	for i, l := range n.Layers {
 		l.fire()
+		if err == nil {
+			err = l.checkValues(i)
+		}
+	}
+	if err != nil {
+		return err
 	}
+	return nil
 }
 
-// Forward computes a forward pass
+// Forward computes a forward pass on the input, scaled by the InputScaler
+// if set. It returns a *NumericError if any neuron value is NaN or infinite.
 func (n *Neural) Forward(input []float64) error {
 	if len(input) != n.Config.Inputs {
 		return fmt.Errorf("Invalid input dimension - expected: %d got: %d", n.Config.Inputs, len(input))
 	}
+	input = n.Config.InputScaler.Transform(input)
 	for _, n := range n.Layers[0].Neurons {
 		for i := 0; i < len(input); i++ {
 			n.In[i].fire(input[i])
 		}
 	}
-	n.fire()
-	return nil
+	return n.fire()
 }
 
-// Predict computes a forward pass and returns a prediction
+// Predict computes a forward pass and returns a prediction, unscaled by
+// the TargetScaler if set
 func (n *Neural) Predict(input []float64) []float64 {
 	n.Forward(input)
+	return n.Config.TargetScaler.InverseTransform(n.output())
+}
 
-	outLayer := n.Layers[len(n.Layers)-1]
-	out := make([]float64, len(outLayer.Neurons))
-	for i, neuron := range outLayer.Neurons {
-		out[i] = neuron.Value
+// PredictE computes a forward pass and returns a prediction,
+// or the error returned by Forward
+func (n *Neural) PredictE(input []float64) ([]float64, error) {
+	if err := n.Forward(input); err != nil {
+		return nil, err
 	}
-	return out
+	return n.Config.TargetScaler.InverseTransform(n.output()), nil
+}
+
+func (n *Neural) output() []float64 {
+	return n.Layers[len(n.Layers)-1].values()
 }
 
 // NumWeights returns the number of weights in the network
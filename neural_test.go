@@ -1,6 +1,8 @@
 package deep
 
 import (
+	"errors"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -86,3 +88,85 @@ func Test_NumWeights(t *testing.T) {
 	n := NewNeural(&Config{Layout: []int{5, 5, 3}})
 	assert.Equal(t, n.NumWeights(), 5*5+3*5)
 }
+
+func Test_ForwardNonFinite(t *testing.T) {
+	n := NewNeural(&Config{
+		Inputs:     2,
+		Layout:     []int{2, 2},
+		Activation: ActivationTanh,
+		Mode:       ModeMultiClass,
+		Weight:     NewNormal(1.0, 0),
+		Bias:       true,
+	})
+
+	assert.NoError(t, n.Forward([]float64{1, 2}))
+
+	err := n.Forward([]float64{math.NaN(), 2})
+	var numeric *NumericError
+	assert.True(t, errors.As(err, &numeric))
+	assert.Equal(t, StageForward, numeric.Stage)
+	assert.Equal(t, 0, numeric.Layer)
+	assert.Equal(t, -1, numeric.Synapse)
+}
+
+func Test_Validate(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			Inputs:     2,
+			Layout:     []int{3, 3},
+			Activation: ActivationTanh,
+			Mode:       ModeMultiClass,
+			Bias:       true,
+		}
+	}
+	assert.NoError(t, valid().Validate())
+
+	tests := []struct {
+		config func(c *Config)
+		err    string
+	}{
+		{func(c *Config) { c.Inputs = 0 }, "Invalid inputs - expected positive, got: 0"},
+		{func(c *Config) { c.Layout = nil }, "Invalid layout - expected at least one layer"},
+		{func(c *Config) { c.Layout = []int{3, 0} }, "Invalid layout - layer 1 has 0 neurons"},
+		{func(c *Config) { c.Layout = []int{3, 1} }, "Invalid layout - MultiClass requires at least 2 outputs, got: 1"},
+		{func(c *Config) { c.Mode = 7 }, "Invalid mode: 7"},
+		{func(c *Config) { c.Activation = ActivationSoftmax }, "Invalid activation - softmax is only supported in the output layer"},
+		{func(c *Config) { c.Loss = 42 }, "Invalid loss - 42 is not registered"},
+		{func(c *Config) { c.Loss = LossBinaryCrossEntropy }, "Invalid loss - BinCE is not compatible with Softmax output in MultiClass mode"},
+		{func(c *Config) { c.Mode, c.Loss = ModeDefault, LossCrossEntropy }, "Invalid loss - CE is not compatible with Tanh output in Default mode"},
+		{func(c *Config) { c.LossOptions.Delta = 2 }, "Invalid loss options - delta only applies to Huber, got: CE"},
+		{func(c *Config) { c.ClassWeights = []float64{1, 2} }, "Invalid class weights - expected: 3 got: 2"},
+		{func(c *Config) { c.ClassWeights = []float64{1, -1, 1} }, "Invalid class weights - class 1 has weight -1"},
+		{func(c *Config) { c.Mode, c.ClassWeights = ModeMultiLabel, []float64{1, 2, 1} }, "Invalid class weights - expected Binary or MultiClass mode, got: MultiLabel"},
+		{func(c *Config) { c.Mode, c.ClassWeights = ModeRegression, []float64{1, 2, 1} }, "Invalid class weights - expected Binary or MultiClass mode, got: Regression"},
+	}
+	for _, test := range tests {
+		c := valid()
+		test.config(c)
+		assert.EqualError(t, c.Validate(), test.err)
+
+		n, err := NewNeuralE(c)
+		assert.Nil(t, n)
+		assert.EqualError(t, err, test.err)
+	}
+}
+
+func Test_PredictE(t *testing.T) {
+	n, err := NewNeuralE(&Config{
+		Inputs:     2,
+		Layout:     []int{2, 1},
+		Activation: ActivationSigmoid,
+		Mode:       ModeBinary,
+		Weight:     NewNormal(1.0, 0),
+		Bias:       true,
+	})
+	assert.NoError(t, err)
+
+	out, err := n.PredictE([]float64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, n.Predict([]float64{1, 2}), out)
+
+	out, err = n.PredictE([]float64{1})
+	assert.Nil(t, out)
+	assert.EqualError(t, err, "Invalid input dimension - expected: 2 got: 1")
+}
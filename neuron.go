@@ -1,10 +1,5 @@
 package deep
 
-import (
-	"fmt"
-	"math"
-)
-
 // Neuron is a neural network node
 type Neuron struct {
 	A     ActivationType `json:"-"`
@@ -24,11 +19,6 @@ func (n *Neuron) fire() {
 	var sum float64
 	for _, s := range n.In {
 		sum += s.Out
-		if math.IsNaN(sum) {
-			fmt.Println("neuron is NaN")
-			sum = 0
-			break
-		}
 	}
 	n.Value = n.Activate(sum)
 
@@ -53,6 +43,10 @@ type Synapse struct {
 	Weight  float64
 	In, Out float64 `json:"-"`
 	IsBias  bool
+	// Pruned synapses are fixed at zero and skipped by trainers
+	Pruned bool
+	// Frozen synapses keep their weight and are skipped by trainers
+	Frozen bool
 }
 
 // NewSynapse returns a synapse with the specified initialized weight
@@ -60,6 +54,11 @@ func NewSynapse(weight float64) *Synapse {
 	return &Synapse{Weight: weight}
 }
 
+// Trainable reports whether trainers may update the synapse weight
+func (s *Synapse) Trainable() bool {
+	return !s.Pruned && !s.Frozen
+}
+
 func (s *Synapse) fire(value float64) {
 	s.In = value
 	s.Out = s.In * s.Weight
@@ -2,16 +2,32 @@ package deep
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
 )
 
 // Dump is a neural network dump
 type Dump struct {
 	Config  *Config
-	Weights [][][]float64
+	Weights [][][]float64 `json:",omitempty"`
+	// Sparse holds the weights of pruned networks, omitting pruned synapses
+	Sparse []*CSR `json:",omitempty"`
 }
 
-// ApplyWeights sets the weights from a three-dimensional slice
+// ApplyWeights sets the weights from a three-dimensional slice,
+// panicking if its shape does not match the network
 func (n *Neural) ApplyWeights(weights [][][]float64) {
+	if err := n.ApplyWeightsE(weights); err != nil {
+		panic(err)
+	}
+}
+
+// ApplyWeightsE sets the weights from a three-dimensional slice,
+// or returns an error if its shape does not match the network
+func (n *Neural) ApplyWeightsE(weights [][][]float64) error {
+	if err := n.checkWeights(weights); err != nil {
+		return err
+	}
 	for i, l := range n.Layers {
 		for j := range l.Neurons {
 			for k := range l.Neurons[j].In {
@@ -19,6 +35,50 @@ func (n *Neural) ApplyWeights(weights [][][]float64) {
 			}
 		}
 	}
+	return nil
+}
+
+func (n *Neural) checkWeights(weights [][][]float64) error {
+	if len(weights) != len(n.Layers) {
+		return fmt.Errorf("Invalid weights - expected %d layers, got: %d", len(n.Layers), len(weights))
+	}
+	for i, l := range n.Layers {
+		if len(weights[i]) != len(l.Neurons) {
+			return fmt.Errorf("Invalid weights - layer %d expected %d neurons, got: %d", i, len(l.Neurons), len(weights[i]))
+		}
+		for j, neuron := range l.Neurons {
+			if len(weights[i][j]) != len(neuron.In) {
+				return fmt.Errorf("Invalid weights - layer %d neuron %d expected %d weights, got: %d", i, j, len(neuron.In), len(weights[i][j]))
+			}
+		}
+	}
+	return nil
+}
+
+func (n *Neural) checkSparse(layers []*CSR) error {
+	if len(layers) != len(n.Layers) {
+		return fmt.Errorf("Invalid sparse weights - expected %d layers, got: %d", len(n.Layers), len(layers))
+	}
+	for i, l := range n.Layers {
+		m := layers[i]
+		if m == nil || m.Rows != len(l.Neurons) || len(m.RowPtr) != len(l.Neurons)+1 {
+			return fmt.Errorf("Invalid sparse weights - layer %d expected %d rows", i, len(l.Neurons))
+		}
+		if len(m.ColIdx) != len(m.Values) || m.RowPtr[0] != 0 || m.RowPtr[m.Rows] != len(m.Values) {
+			return fmt.Errorf("Invalid sparse weights - layer %d has inconsistent row pointers", i)
+		}
+		for j, neuron := range l.Neurons {
+			if m.RowPtr[j] > m.RowPtr[j+1] {
+				return fmt.Errorf("Invalid sparse weights - layer %d has inconsistent row pointers", i)
+			}
+			for p := m.RowPtr[j]; p < m.RowPtr[j+1]; p++ {
+				if c := m.ColIdx[p]; c < 0 || c >= len(neuron.In) {
+					return fmt.Errorf("Invalid sparse weights - layer %d neuron %d has no synapse %d", i, j, c)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // Weights returns all weights in sequence
@@ -36,8 +96,47 @@ func (n Neural) Weights() [][][]float64 {
 	return weights
 }
 
-// Dump generates a network dump
+// ApplySparse sets the weights from per-layer sparse matrices,
+// marking synapses absent from the matrices as pruned
+func (n *Neural) ApplySparse(layers []*CSR) {
+	for i, l := range n.Layers {
+		m := layers[i]
+		for j, neuron := range l.Neurons {
+			for _, s := range neuron.In {
+				s.Weight, s.Pruned = 0, !s.IsBias
+			}
+			for p := m.RowPtr[j]; p < m.RowPtr[j+1]; p++ {
+				s := neuron.In[m.ColIdx[p]]
+				s.Weight, s.Pruned = m.Values[p], false
+			}
+		}
+	}
+}
+
+// Clone returns a deep copy of n, including pruning and freezing
+func (n *Neural) Clone() *Neural {
+	c := n.copyConfig()
+	c.ClassWeights = slices.Clone(n.Config.ClassWeights)
+	clone := NewNeural(c)
+	for i, l := range clone.Layers {
+		for j, neuron := range l.Neurons {
+			for k, s := range neuron.In {
+				src := n.Layers[i].Neurons[j].In[k]
+				s.Weight, s.Pruned, s.Frozen = src.Weight, src.Pruned, src.Frozen
+			}
+		}
+	}
+	return clone
+}
+
+// Dump generates a network dump, storing pruned networks sparsely
 func (n Neural) Dump() *Dump {
+	if n.IsPruned() {
+		return &Dump{
+			Config: n.Config,
+			Sparse: n.Sparse().Layers,
+		}
+	}
 	return &Dump{
 		Config:  n.Config,
 		Weights: n.Weights(),
@@ -46,12 +145,54 @@ func (n Neural) Dump() *Dump {
 
 // FromDump restores a Neural from a dump
 func FromDump(dump *Dump) *Neural {
+	upgrade(dump.Config)
 	n := NewNeural(dump.Config)
-	n.ApplyWeights(dump.Weights)
+	if dump.Sparse != nil {
+		n.ApplySparse(dump.Sparse)
+	} else {
+		n.ApplyWeights(dump.Weights)
+	}
 
 	return n
 }
 
+// FromDumpE restores a Neural from a dump, or returns an error if the
+// config is invalid or the weights do not match it
+func FromDumpE(dump *Dump) (*Neural, error) {
+	if dump == nil || dump.Config == nil {
+		return nil, fmt.Errorf("Invalid dump - missing config")
+	}
+	upgrade(dump.Config)
+	n, err := NewNeuralE(dump.Config)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case dump.Sparse != nil:
+		if err := n.checkSparse(dump.Sparse); err != nil {
+			return nil, err
+		}
+		n.ApplySparse(dump.Sparse)
+	case dump.Weights != nil:
+		if err := n.ApplyWeightsE(dump.Weights); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("Invalid dump - missing weights")
+	}
+	return n, nil
+}
+
+// upgrade updates configs dumped by earlier versions. These defaulted
+// multi-label networks to cross entropy over their sigmoid outputs, which
+// was trained as binary cross entropy.
+func upgrade(c *Config) {
+	if c.Mode == ModeMultiLabel && c.Loss == LossCrossEntropy && len(c.Heads) == 0 &&
+		c.HeadActivation(Head{Mode: c.Mode}) == ActivationSigmoid {
+		c.Loss = LossBinaryCrossEntropy
+	}
+}
+
 // Marshal marshals to JSON from network
 func (n Neural) Marshal() ([]byte, error) {
 	return json.Marshal(n.Dump())
@@ -63,5 +204,5 @@ func Unmarshal(bytes []byte) (*Neural, error) {
 	if err := json.Unmarshal(bytes, &dump); err != nil {
 		return nil, err
 	}
-	return FromDump(&dump), nil
+	return FromDumpE(&dump)
 }
@@ -55,3 +55,138 @@ func Test_Marshal(t *testing.T) {
 	assert.Equal(t, n.String(), new.String())
 	assert.Equal(t, n.Predict([]float64{0}), new.Predict([]float64{0}))
 }
+
+func Test_MarshalPruned(t *testing.T) {
+	rand.Seed(0)
+
+	n := NewNeural(&Config{
+		Inputs:     4,
+		Layout:     []int{16, 16, 2},
+		Activation: ActivationReLU,
+		Mode:       ModeMultiClass,
+		Weight:     NewNormal(1, 0),
+		Bias:       true,
+	})
+	dense, err := n.Marshal()
+	assert.Nil(t, err)
+
+	n.Prune(0.9, PruneGlobal)
+	dump := n.Dump()
+	assert.Nil(t, dump.Weights)
+	assert.Len(t, dump.Sparse, len(n.Layers))
+
+	sparse, err := n.Marshal()
+	assert.Nil(t, err)
+	assert.Less(t, len(sparse), len(dense))
+
+	new, err := Unmarshal(sparse)
+	assert.Nil(t, err)
+	assert.Equal(t, n.Sparsity(), new.Sparsity())
+	assert.Equal(t, n.String(), new.String())
+	assert.Equal(t, n.Predict([]float64{1, 2, 3, 4}), new.Predict([]float64{1, 2, 3, 4}))
+}
+
+type doubledSquared struct{ MeanSquared }
+
+var lossDoubledSquared = RegisterLoss("doubled-mse", doubledSquared{})
+
+func Test_MarshalRegisteredLoss(t *testing.T) {
+	loss := lossDoubledSquared
+
+	n := NewNeural(&Config{
+		Inputs:     1,
+		Layout:     []int{2, 1},
+		Activation: ActivationSigmoid,
+		Mode:       ModeRegression,
+		Loss:       loss,
+		Bias:       true,
+	})
+
+	dump, err := n.Marshal()
+	assert.Nil(t, err)
+	assert.Contains(t, string(dump), `"Loss":"doubled-mse"`)
+
+	new, err := Unmarshal(dump)
+	assert.Nil(t, err)
+	assert.Equal(t, loss, new.Config.Loss)
+
+	_, err = Unmarshal([]byte(`{"Config":{"Inputs":1,"Layout":[1],"Loss":"missing"}}`))
+	assert.Error(t, err)
+}
+
+func Test_MarshalLossOptions(t *testing.T) {
+	n := NewNeural(&Config{
+		Inputs:      1,
+		Layout:      []int{2, 1},
+		Activation:  ActivationSigmoid,
+		Mode:        ModeRegression,
+		Loss:        LossHuber,
+		LossOptions: LossOptions{Delta: 0.5},
+		Bias:        true,
+	})
+
+	dump, err := n.Marshal()
+	assert.Nil(t, err)
+
+	new, err := Unmarshal(dump)
+	assert.Nil(t, err)
+	assert.Equal(t, Huber{Delta: 0.5}, new.Config.HeadLoss(new.Config.OutputHeads()[0]))
+}
+
+func Test_FromDumpE(t *testing.T) {
+	rand.Seed(0)
+
+	n := NewNeural(&Config{
+		Inputs:     2,
+		Layout:     []int{3, 1},
+		Activation: ActivationSigmoid,
+		Mode:       ModeBinary,
+		Weight:     NewUniform(0.5, 0),
+		Bias:       true,
+	})
+
+	restored, err := FromDumpE(n.Dump())
+	assert.NoError(t, err)
+	assert.Equal(t, n.Predict([]float64{1, 0}), restored.Predict([]float64{1, 0}))
+
+	dump := n.Dump()
+	dump.Weights[1][0] = dump.Weights[1][0][:2]
+	_, err = FromDumpE(dump)
+	assert.EqualError(t, err, "Invalid weights - layer 1 neuron 0 expected 4 weights, got: 2")
+	assert.Panics(t, func() { n.ApplyWeights(dump.Weights) })
+
+	dump = n.Dump()
+	config := *n.Config
+	config.Layout = []int{3, 3, 1}
+	dump.Config = &config
+	_, err = FromDumpE(dump)
+	assert.EqualError(t, err, "Invalid weights - expected 3 layers, got: 2")
+
+	dump = n.Dump()
+	dump.Weights = nil
+	_, err = FromDumpE(dump)
+	assert.EqualError(t, err, "Invalid dump - missing weights")
+
+	n.Prune(0.5, PruneGlobal)
+	dump = n.Dump()
+	dump.Sparse[0].ColIdx[0] = 9
+	_, err = FromDumpE(dump)
+	assert.EqualError(t, err, "Invalid sparse weights - layer 0 neuron 0 has no synapse 9")
+
+	_, err = Unmarshal([]byte(`{"Config":{"Inputs":2,"Layout":[3,1],"Mode":3,"Loss":"CE"},"Weights":[]}`))
+	assert.EqualError(t, err, "Invalid loss - CE is not compatible with Sigmoid output in Binary mode")
+}
+
+func Test_UnmarshalLegacyMultiLabel(t *testing.T) {
+	// dumped by the baseline with the then default multi-label loss, cross entropy
+	legacy := `{"Config":{"Inputs":2,"Layout":[2,2],"Activation":1,"Mode":4,"Loss":1,"Bias":true},` +
+		`"Weights":[[[-0.037681251464367155,0.09341153643355471,-0.2015152405427577],` +
+		`[-0.2171814903912619,-0.17174037263360437,-0.09954406970735646]],` +
+		`[[0.052330143989809785,0.08228002660924522,0.0076063142510326975],` +
+		`[0.22025454402250622,-0.031142906406509907,0.15681998049504842]]]}`
+
+	n, err := Unmarshal([]byte(legacy))
+	assert.NoError(t, err)
+	assert.Equal(t, LossBinaryCrossEntropy, n.Config.Loss)
+	assert.InDeltaSlice(t, []float64{0.5163281453805688, 0.5598892855807447}, n.Predict([]float64{1, 0}), 1e-12)
+}
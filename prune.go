@@ -0,0 +1,96 @@
+package deep
+
+import (
+	"math"
+	"sort"
+)
+
+// PruneScope determines how a pruning target is distributed over layers
+type PruneScope int
+
+const (
+	// PruneGlobal ranks all weights of the network together
+	PruneGlobal PruneScope = 0
+	// PruneLayerwise prunes every layer to the target sparsity
+	PruneLayerwise PruneScope = 1
+)
+
+// Prune zeroes the smallest weights by magnitude until the given fraction
+// of (non-bias) weights is pruned. Pruned synapses stay at zero during training.
+func (n *Neural) Prune(sparsity float64, scope PruneScope) {
+	if scope == PruneLayerwise {
+		for i := range n.Layers {
+			n.PruneLayer(i, sparsity)
+		}
+		return
+	}
+	var synapses []*Synapse
+	for _, l := range n.Layers {
+		synapses = append(synapses, l.prunable()...)
+	}
+	prune(synapses, sparsity)
+}
+
+// PruneLayer prunes layer i to the given sparsity
+func (n *Neural) PruneLayer(i int, sparsity float64) {
+	prune(n.Layers[i].prunable(), sparsity)
+}
+
+// Sparsity returns the fraction of (non-bias) weights that are pruned
+func (n *Neural) Sparsity() float64 {
+	var pruned, total int
+	for _, l := range n.Layers {
+		for _, s := range l.prunable() {
+			if s.Pruned {
+				pruned++
+			}
+			total++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(pruned) / float64(total)
+}
+
+// IsPruned reports whether any synapse in the network is pruned
+func (n *Neural) IsPruned() bool {
+	for _, l := range n.Layers {
+		for _, neuron := range l.Neurons {
+			for _, s := range neuron.In {
+				if s.Pruned {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (l *Layer) prunable() []*Synapse {
+	var synapses []*Synapse
+	for _, n := range l.Neurons {
+		for _, s := range n.In {
+			if !s.IsBias {
+				synapses = append(synapses, s)
+			}
+		}
+	}
+	return synapses
+}
+
+func prune(synapses []*Synapse, sparsity float64) {
+	sparsity = math.Max(0, math.Min(1, sparsity))
+	k := int(math.Round(sparsity * float64(len(synapses))))
+
+	sort.SliceStable(synapses, func(i, j int) bool {
+		if synapses[i].Pruned != synapses[j].Pruned {
+			return synapses[i].Pruned
+		}
+		return math.Abs(synapses[i].Weight) < math.Abs(synapses[j].Weight)
+	})
+	for _, s := range synapses[:k] {
+		s.Pruned = true
+		s.Weight = 0
+	}
+}
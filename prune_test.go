@@ -0,0 +1,72 @@
+package deep
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PruneGlobal(t *testing.T) {
+	rand.Seed(0)
+	n := NewNeural(&Config{
+		Inputs:     4,
+		Layout:     []int{8, 8, 2},
+		Activation: ActivationReLU,
+		Mode:       ModeMultiClass,
+		Weight:     NewNormal(1, 0),
+		Bias:       true,
+	})
+
+	original := n.Weights()
+	n.Prune(0.75, PruneGlobal)
+	assert.InDelta(t, 0.75, n.Sparsity(), 0.01)
+	assert.True(t, n.IsPruned())
+
+	var largestPruned, smallestKept = 0.0, math.Inf(1)
+	for i, l := range n.Layers {
+		for j, neuron := range l.Neurons {
+			for k, s := range neuron.In {
+				w := math.Abs(original[i][j][k])
+				switch {
+				case s.IsBias:
+					assert.False(t, s.Pruned)
+				case s.Pruned:
+					assert.Equal(t, 0.0, s.Weight)
+					largestPruned = math.Max(largestPruned, w)
+				default:
+					smallestKept = math.Min(smallestKept, w)
+				}
+			}
+		}
+	}
+	assert.LessOrEqual(t, largestPruned, smallestKept)
+}
+
+func Test_PruneLayerwise(t *testing.T) {
+	rand.Seed(0)
+	n := NewNeural(&Config{
+		Inputs:     10,
+		Layout:     []int{10, 10, 4},
+		Activation: ActivationTanh,
+		Mode:       ModeRegression,
+		Weight:     NewNormal(1, 0),
+		Bias:       true,
+	})
+
+	n.Prune(0.5, PruneLayerwise)
+	for _, l := range n.Layers {
+		var pruned int
+		synapses := l.prunable()
+		for _, s := range synapses {
+			if s.Pruned {
+				pruned++
+			}
+		}
+		assert.Equal(t, len(synapses)/2, pruned)
+	}
+
+	n.Prune(0.25, PruneLayerwise)
+	assert.InDelta(t, 0.5, n.Sparsity(), 1e-9, "pruning is never undone")
+}
@@ -0,0 +1,132 @@
+package deep
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// InputGradient returns the gradient of the given output, as returned by
+// Predict, with respect to the input
+func (n *Neural) InputGradient(input []float64, output int) ([]float64, error) {
+	out := n.Layers[len(n.Layers)-1]
+	if output < 0 || output >= len(out.Neurons) {
+		return nil, fmt.Errorf("Invalid output - expected 0 to %d, got: %d", len(out.Neurons)-1, output)
+	}
+	if err := n.Forward(input); err != nil {
+		return nil, err
+	}
+
+	deltas := make([]float64, len(out.Neurons))
+	if start, end, ok := out.softmaxSpan(output); ok {
+		// softmax Jacobian: ∂s_o/∂z_j = s_o(1[j=o] - s_j)
+		so := out.Neurons[output].Value
+		for j := start; j < end; j++ {
+			deltas[j] = -so * out.Neurons[j].Value
+		}
+		deltas[output] += so
+	} else {
+		neuron := out.Neurons[output]
+		deltas[output] = neuron.DActivate(neuron.Value)
+	}
+	if t := n.Config.TargetScaler; t != nil {
+		deltas[output] *= t.Scale[output]
+	}
+	return n.BackpropInput(deltas), nil
+}
+
+// BackpropInput backpropagates deltas, the gradient with respect to the input
+// sums of the output layer in the last forward pass, to the unscaled input
+func (n *Neural) BackpropInput(deltas []float64) []float64 {
+	for i := len(n.Layers) - 2; i >= 0; i-- {
+		next := make([]float64, len(n.Layers[i].Neurons))
+		for j, neuron := range n.Layers[i].Neurons {
+			var sum float64
+			for k, s := range neuron.Out {
+				sum += s.Weight * deltas[k]
+			}
+			next[j] = neuron.DActivate(neuron.Value) * sum
+		}
+		deltas = next
+	}
+
+	gradient := make([]float64, n.Config.Inputs)
+	for j, neuron := range n.Layers[0].Neurons {
+		for r := range gradient {
+			gradient[r] += neuron.In[r].Weight * deltas[j]
+		}
+	}
+	if s := n.Config.InputScaler; s != nil {
+		for r := range gradient {
+			gradient[r] /= s.Scale[r]
+		}
+	}
+	return gradient
+}
+
+// SmoothGrad averages the input gradient of output over samples copies of
+// the input perturbed by Gaussian noise with standard deviation sigma
+func (n *Neural) SmoothGrad(input []float64, output, samples int, sigma float64) ([]float64, error) {
+	samples = max(samples, 1)
+	saliency := make([]float64, len(input))
+	noisy := make([]float64, len(input))
+	for s := 0; s < samples; s++ {
+		for i, x := range input {
+			noisy[i] = x + rand.NormFloat64()*sigma
+		}
+		gradient, err := n.InputGradient(noisy, output)
+		if err != nil {
+			return nil, err
+		}
+		for i, g := range gradient {
+			saliency[i] += g / float64(samples)
+		}
+	}
+	return saliency, nil
+}
+
+// IntegratedGradients attributes output to each input by integrating the input
+// gradient along the straight path from baseline to input in the given number
+// of steps. A nil baseline is all zeros. The attributions sum approximately to
+// the difference in output between input and baseline.
+func (n *Neural) IntegratedGradients(input, baseline []float64, output, steps int) ([]float64, error) {
+	if baseline == nil {
+		baseline = make([]float64, len(input))
+	}
+	if len(baseline) != len(input) {
+		return nil, fmt.Errorf("Invalid baseline dimension - expected: %d got: %d", len(input), len(baseline))
+	}
+	steps = max(steps, 1)
+	attributions := make([]float64, len(input))
+	point := make([]float64, len(input))
+	for s := 1; s <= steps; s++ {
+		// right Riemann sum
+		alpha := float64(s) / float64(steps)
+		for i := range point {
+			point[i] = baseline[i] + alpha*(input[i]-baseline[i])
+		}
+		gradient, err := n.InputGradient(point, output)
+		if err != nil {
+			return nil, err
+		}
+		for i, g := range gradient {
+			attributions[i] += g / float64(steps)
+		}
+	}
+	for i := range attributions {
+		attributions[i] *= input[i] - baseline[i]
+	}
+	return attributions, nil
+}
+
+// softmaxSpan returns the range of neurons sharing a softmax with neuron j, if any
+func (l *Layer) softmaxSpan(j int) (int, int, bool) {
+	if l.A == ActivationSoftmax {
+		return 0, len(l.Neurons), true
+	}
+	for _, s := range l.spans {
+		if s.a == ActivationSoftmax && j >= s.start && j < s.end {
+			return s.start, s.end, true
+		}
+	}
+	return 0, 0, false
+}
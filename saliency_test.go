@@ -0,0 +1,87 @@
+package deep
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func numericalInputGradient(n *Neural, input []float64, output int) []float64 {
+	const epsilon = 1e-6
+	gradient := make([]float64, len(input))
+	x := append([]float64(nil), input...)
+	for i := range x {
+		x[i] = input[i] + epsilon
+		plus := n.Predict(x)[output]
+		x[i] = input[i] - epsilon
+		minus := n.Predict(x)[output]
+		x[i] = input[i]
+		gradient[i] = (plus - minus) / (2 * epsilon)
+	}
+	return gradient
+}
+
+func Test_InputGradient(t *testing.T) {
+	input := []float64{0.3, -0.7, 0.9}
+	for _, mode := range []Mode{ModeMultiClass, ModeRegression, ModeBinary, ModeMultiLabel} {
+		rand.Seed(0)
+		n := NewNeural(&Config{
+			Inputs:     3,
+			Layout:     []int{5, 4, 3},
+			Activation: ActivationTanh,
+			Mode:       mode,
+			Weight:     NewNormal(1, 0),
+			Bias:       true,
+		})
+		for o := 0; o < 3; o++ {
+			gradient, err := n.InputGradient(input, o)
+			assert.NoError(t, err)
+			assert.InDeltaSlice(t, numericalInputGradient(n, input, o), gradient, 1e-6, "mode %s output %d", mode, o)
+		}
+	}
+
+	n := headsNetwork()
+	for o := 0; o < 6; o++ {
+		gradient, err := n.InputGradient(input, o)
+		assert.NoError(t, err)
+		assert.InDeltaSlice(t, numericalInputGradient(n, input, o), gradient, 1e-6, "head output %d", o)
+	}
+
+	_, err := n.InputGradient(input, 6)
+	assert.EqualError(t, err, "Invalid output - expected 0 to 5, got: 6")
+}
+
+func Test_SmoothGrad(t *testing.T) {
+	n := headsNetwork()
+	input := []float64{0.3, -0.7, 0.9}
+
+	gradient, _ := n.InputGradient(input, 1)
+	smooth, err := n.SmoothGrad(input, 1, 5, 0)
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, gradient, smooth, 1e-12)
+
+	smooth, err = n.SmoothGrad(input, 1, 50, 0.1)
+	assert.NoError(t, err)
+	assert.Len(t, smooth, 3)
+	assert.NotEqual(t, gradient, smooth)
+}
+
+func Test_IntegratedGradients(t *testing.T) {
+	n := headsNetwork()
+	input, baseline := []float64{0.3, -0.7, 0.9}, []float64{0.1, 0.1, 0.1}
+
+	for _, o := range []int{0, 3, 5} {
+		attributions, err := n.IntegratedGradients(input, baseline, o, 500)
+		assert.NoError(t, err)
+
+		var sum float64
+		for _, a := range attributions {
+			sum += a
+		}
+		assert.InDelta(t, n.Predict(input)[o]-n.Predict(baseline)[o], sum, 1e-2)
+	}
+
+	_, err := n.IntegratedGradients(input, []float64{0}, 0, 10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package deep
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ScalerType is a feature scaling method
+type ScalerType int
+
+const (
+	// ScaleStandard shifts each feature to μ=0 σ=1
+	ScaleStandard ScalerType = 0
+	// ScaleMinMax scales each feature to (0,1)
+	ScaleMinMax ScalerType = 1
+	// ScaleRobust centers each feature on its median and scales by its interquartile range
+	ScaleRobust ScalerType = 2
+)
+
+func (t ScalerType) String() string {
+	switch t {
+	case ScaleStandard:
+		return "standard"
+	case ScaleMinMax:
+		return "min-max"
+	case ScaleRobust:
+		return "robust"
+	}
+	return "N/A"
+}
+
+// Scaler is a per-feature transform x' = (x - Center) / Scale fitted on
+// training data. Scalers set on a Config are persisted with the model.
+type Scaler struct {
+	Type   ScalerType
+	Center []float64
+	Scale  []float64
+}
+
+// NewScaler returns an unfitted scaler
+func NewScaler(t ScalerType) *Scaler {
+	return &Scaler{Type: t}
+}
+
+// Fit computes the center and scale of each column of rows.
+// Constant features get a scale of 1 and are only centered, so they are
+// not rescaled if they vary in prediction, e.g. pixels blank in all rows.
+func (s *Scaler) Fit(rows [][]float64) error {
+	if len(rows) == 0 {
+		return fmt.Errorf("Invalid data - expected at least one row")
+	}
+	features := len(rows[0])
+	for i, row := range rows {
+		if len(row) != features {
+			return fmt.Errorf("Invalid data - row %d expected: %d got: %d", i, features, len(row))
+		}
+	}
+
+	s.Center, s.Scale = make([]float64, features), make([]float64, features)
+	column := make([]float64, len(rows))
+	for j := 0; j < features; j++ {
+		for i, row := range rows {
+			column[i] = row[j]
+		}
+		switch s.Type {
+		case ScaleStandard:
+			s.Center[j], s.Scale[j] = Mean(column), StandardDeviation(column)
+		case ScaleMinMax:
+			s.Center[j], s.Scale[j] = Min(column), Max(column)-Min(column)
+		case ScaleRobust:
+			slices.Sort(column)
+			s.Center[j], s.Scale[j] = quantile(column, 0.5), quantile(column, 0.75)-quantile(column, 0.25)
+		default:
+			return fmt.Errorf("Invalid scaler: %d", s.Type)
+		}
+		if s.Scale[j] == 0 || !IsFinite(s.Scale[j]) {
+			s.Scale[j] = 1
+		}
+	}
+	return nil
+}
+
+// Transform returns a scaled copy of x. A nil scaler returns x.
+func (s *Scaler) Transform(x []float64) []float64 {
+	if s == nil {
+		return x
+	}
+	y := make([]float64, len(x))
+	for i, v := range x {
+		y[i] = (v - s.Center[i]) / s.Scale[i]
+	}
+	return y
+}
+
+// InverseTransform returns an unscaled copy of x. A nil scaler returns x.
+func (s *Scaler) InverseTransform(x []float64) []float64 {
+	if s == nil {
+		return x
+	}
+	y := make([]float64, len(x))
+	for i, v := range x {
+		y[i] = v*s.Scale[i] + s.Center[i]
+	}
+	return y
+}
+
+func (s *Scaler) validate(name string, features int) error {
+	if len(s.Center) != features || len(s.Scale) != features {
+		return fmt.Errorf("Invalid %s scaler - expected %d features, got: %d", name, features, len(s.Center))
+	}
+	for i := range s.Scale {
+		if s.Scale[i] == 0 || !IsFinite(s.Scale[i]) || !IsFinite(s.Center[i]) {
+			return fmt.Errorf("Invalid %s scaler - feature %d has center %v and scale %v", name, i, s.Center[i], s.Scale[i])
+		}
+	}
+	return nil
+}
+
+// resize returns a copy of s for the given number of features,
+// new features are left unscaled
+func (s *Scaler) resize(features int) *Scaler {
+	if s == nil {
+		return nil
+	}
+	r := &Scaler{Type: s.Type, Center: make([]float64, features), Scale: make([]float64, features)}
+	copy(r.Center, s.Center)
+	for i := range r.Scale {
+		r.Scale[i] = 1
+	}
+	copy(r.Scale, s.Scale)
+	return r
+}
+
+func (s *Scaler) clone() *Scaler {
+	if s == nil {
+		return nil
+	}
+	return &Scaler{Type: s.Type, Center: slices.Clone(s.Center), Scale: slices.Clone(s.Scale)}
+}
+
+// quantile of sorted values with linear interpolation
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
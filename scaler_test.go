@@ -0,0 +1,99 @@
+package deep
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var scalerData = [][]float64{
+	{1, 10, 5},
+	{2, 20, 5},
+	{3, 30, 5},
+	{4, 100, 5},
+}
+
+func Test_ScalerFit(t *testing.T) {
+	standard := NewScaler(ScaleStandard)
+	assert.NoError(t, standard.Fit(scalerData))
+	assert.InDeltaSlice(t, []float64{2.5, 40, 5}, standard.Center, 1e-9)
+	assert.InDelta(t, StandardDeviation([]float64{1, 2, 3, 4}), standard.Scale[0], 1e-9)
+	assert.Equal(t, 1.0, standard.Scale[2])
+
+	minmax := NewScaler(ScaleMinMax)
+	assert.NoError(t, minmax.Fit(scalerData))
+	assert.Equal(t, []float64{1, 10, 5}, minmax.Center)
+	assert.Equal(t, []float64{3, 90, 1}, minmax.Scale)
+	assert.InDeltaSlice(t, []float64{1, 1, 0}, minmax.Transform([]float64{4, 100, 5}), 1e-9)
+
+	robust := NewScaler(ScaleRobust)
+	assert.NoError(t, robust.Fit(scalerData))
+	assert.InDeltaSlice(t, []float64{2.5, 25, 5}, robust.Center, 1e-9)
+	assert.InDeltaSlice(t, []float64{1.5, 30, 1}, robust.Scale, 1e-9)
+
+	for _, s := range []*Scaler{standard, minmax, robust} {
+		for _, row := range scalerData {
+			assert.InDeltaSlice(t, row, s.InverseTransform(s.Transform(row)), 1e-9, s.Type.String())
+		}
+	}
+
+	assert.Error(t, NewScaler(ScaleStandard).Fit(nil))
+	assert.Error(t, NewScaler(ScaleStandard).Fit([][]float64{{1, 2}, {1}}))
+
+	var none *Scaler
+	assert.Equal(t, []float64{1, 2}, none.Transform([]float64{1, 2}))
+}
+
+func Test_ScalerPersisted(t *testing.T) {
+	rand.Seed(0)
+
+	inputs := NewScaler(ScaleStandard)
+	assert.NoError(t, inputs.Fit([][]float64{{0, 100}, {10, 300}}))
+	targets := NewScaler(ScaleMinMax)
+	assert.NoError(t, targets.Fit([][]float64{{-50}, {50}}))
+
+	n := NewNeural(&Config{
+		Inputs:       2,
+		Layout:       []int{3, 1},
+		Activation:   ActivationTanh,
+		Mode:         ModeRegression,
+		Bias:         true,
+		InputScaler:  inputs,
+		TargetScaler: targets,
+	})
+	assert.NoError(t, n.Config.Validate())
+
+	raw := []float64{5, 200}
+	n.Config.InputScaler = nil
+	n.Forward(inputs.Transform(raw))
+	unscaled := n.output()[0]*100 - 50
+	n.Config.InputScaler = inputs
+	assert.InDelta(t, unscaled, n.Predict(raw)[0], 1e-9)
+
+	dump, err := n.Marshal()
+	assert.NoError(t, err)
+	restored, err := Unmarshal(dump)
+	assert.NoError(t, err)
+	assert.Equal(t, inputs, restored.Config.InputScaler)
+	assert.InDelta(t, unscaled, restored.Predict(raw)[0], 1e-9)
+	assert.InDelta(t, unscaled, n.Sparse().Predict(raw)[0], 1e-9)
+}
+
+func Test_ScalerValidate(t *testing.T) {
+	scaler := NewScaler(ScaleStandard)
+	assert.NoError(t, scaler.Fit([][]float64{{0, 1}, {1, 0}}))
+
+	c := &Config{Inputs: 3, Layout: []int{2}, Mode: ModeRegression, InputScaler: scaler}
+	assert.Error(t, c.Validate())
+	c.Inputs = 2
+	assert.NoError(t, c.Validate())
+
+	c.TargetScaler = scaler
+	assert.NoError(t, c.Validate())
+	c.Mode = ModeMultiClass
+	assert.Error(t, c.Validate())
+
+	c.Mode, c.TargetScaler = ModeRegression, &Scaler{Center: []float64{0, 0}, Scale: []float64{1, 0}}
+	assert.Error(t, c.Validate())
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -51,7 +52,7 @@ func initialize() *mnist.Neural {
 		panic(err)
 	}
 
-	err = network.Train(mnist.TrainingConfig{
+	err = network.Train(context.Background(), mnist.TrainingConfig{
 		TrainingSet: train,
 		TestSet:     test,
 		Iterations:  iterations,
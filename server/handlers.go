@@ -14,8 +14,16 @@ const (
 	incorrectSet      = "dist/incorrect_drawings.csv"
 	additionalSet     = "dist/additional_drawings.csv"
 	correctionWeights = "dist/correction_weights.json"
+	generationsFile   = "dist/generations.json"
 
+	maxGenerations = 3
+
+	// iterations is an upper bound, training stops early once the held out loss stops improving
 	iterations = 100
+	patience   = 5
+
+	// corrections of misclassified drawings weigh more than ordinary samples
+	correctionWeight = 5
 )
 
 var synthesizer = utils.SyntheticConfig{
@@ -62,11 +70,14 @@ func Predict(c echo.Context) error {
 	}
 	_ = utils.SaveImage(img, "dist/image.png")
 
-	tensor := utils.ImageToTensor(img)
+	pixels := utils.ImageToBytes(img)
 
-	fmt.Println(utils.String(tensor))
+	fmt.Println(utils.String(pixels))
 
-	prediction := neuralNetwork.Predict(tensor)
+	prediction := neuralNetwork.Predict(pixels)
+	if generations.Ensemble != nil && len(generations.Members) > 1 {
+		prediction = generations.Predict(pixels)
+	}
 	predictedIndex := mnist.Decode(prediction)
 
 	if req.Expected != nil {
@@ -141,6 +152,9 @@ func Train(c echo.Context) error {
 	if err != nil {
 		return c.JSON(500, utils.WrapError("could not load correction set", err))
 	}
+	for i := range correctionSet {
+		correctionSet[i].Weight = correctionWeight
+	}
 
 	additionalSet, err := mnist.Examples(additionalSet)
 	if err != nil {
@@ -157,13 +171,16 @@ func Train(c echo.Context) error {
 	trainSet, testSet := allData.Split(0.8)
 
 	config := mnist.TrainingConfig{
-		TrainingSet: trainSet,
-		TestSet:     testSet,
-		Iterations:  iterations,
-		Trainer:     mnist.Trainer(),
+		TrainingSet:    trainSet,
+		TestSet:        testSet,
+		Iterations:     iterations,
+		Trainer:        mnist.Trainer(),
+		BalanceClasses: true,
+		FreezeTrunk:    true,
+		Callbacks:      []training.Callback{training.NewEarlyStopping(training.MetricLoss, patience, 1e-4)},
 	}
 
-	if err := neuralNetwork.Train(config); err != nil {
+	if err := neuralNetwork.Train(c.Request().Context(), config); err != nil {
 		return c.JSON(500, utils.WrapError("could not train neural network", err))
 	}
 
@@ -171,5 +188,12 @@ func Train(c echo.Context) error {
 		return c.JSON(500, utils.WrapError("could not save neural network", err))
 	}
 
+	if err := generations.Add(neuralNetwork); err != nil {
+		return c.JSON(500, utils.WrapError("could not add generation", err))
+	}
+	if err := generations.Save(generationsFile); err != nil {
+		return c.JSON(500, utils.WrapError("could not save generations", err))
+	}
+
 	return c.File(correctionWeights)
 }
@@ -0,0 +1,73 @@
+package mnist
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/patrikeh/go-deep/server/types"
+)
+
+// Generations is an ensemble of successively retrained networks
+type Generations struct {
+	*deep.Ensemble
+	Max int
+}
+
+func LoadGenerations(path string, max int) (*Generations, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var dump deep.EnsembleDump
+	if err := json.NewDecoder(f).Decode(&dump); err != nil {
+		return nil, err
+	}
+	ensemble, err := deep.FromEnsembleDump(&dump)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	for _, m := range ensemble.Members {
+		scalePixels(m)
+	}
+	return &Generations{Ensemble: ensemble, Max: max}, nil
+}
+
+// Add snapshots n as the newest generation, dropping the oldest beyond Max
+func (g *Generations) Add(n *Neural) error {
+	snapshot := n.network().Clone()
+	if g.Ensemble == nil {
+		ensemble, err := deep.NewEnsemble(snapshot)
+		if err != nil {
+			return err
+		}
+		g.Ensemble = ensemble
+		return nil
+	}
+	if err := g.Ensemble.Add(snapshot, 1); err != nil {
+		return err
+	}
+	if drop := len(g.Members) - g.Max; g.Max > 0 && drop > 0 {
+		g.Members = g.Members[drop:]
+		if g.Weights != nil {
+			g.Weights = g.Weights[drop:]
+		}
+	}
+	return nil
+}
+
+func (g *Generations) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(g.Dump())
+}
+
+func (g *Generations) Predict(in []types.Byte) []float64 {
+	return g.Ensemble.Predict(types.Coerce[types.Byte, float64](in))
+}
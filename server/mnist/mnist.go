@@ -2,6 +2,7 @@ package mnist
 
 import (
 	"bufio"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -33,12 +34,13 @@ func init() {
 
 func New(inputSize int) *Neural {
 	return (*Neural)(deep.NewNeural(&deep.Config{
-		Inputs:     inputSize,
-		Layout:     []int{50, 10},
-		Activation: deep.ActivationReLU,
-		Mode:       deep.ModeMultiClass,
-		Weight:     deep.NewNormal(0.6, 0.1), // slight positive bias helps ReLU
-		Bias:       true,
+		Inputs:      inputSize,
+		Layout:      []int{50, 10},
+		Activation:  deep.ActivationReLU,
+		Mode:        deep.ModeMultiClass,
+		Weight:      deep.NewNormal(0.6, 0.1), // slight positive bias helps ReLU
+		Bias:        true,
+		InputScaler: pixelScaler(inputSize),
 	}))
 }
 
@@ -54,8 +56,12 @@ func Load(path string) (*Neural, error) {
 		return nil, err
 	}
 
-	neural := (*Neural)(deep.FromDump(&dump))
-	return neural, nil
+	neural, err := deep.FromDumpE(&dump)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	scalePixels(neural)
+	return (*Neural)(neural), nil
 }
 
 type TrainingConfig struct {
@@ -63,6 +69,14 @@ type TrainingConfig struct {
 	TestSet     training.Examples
 	Iterations  int
 	Trainer     training.Trainer
+	// BalanceClasses upweights rare digits for this training run
+	BalanceClasses bool
+	// FreezeTrunk only trains the output layer, keeping the learned features
+	FreezeTrunk bool
+	// Diagnostics periodically reports weight, activation and gradient statistics
+	Diagnostics *training.Diagnostics
+	// Callbacks are attached to the trainer for this training run, e.g. early stopping
+	Callbacks []training.Callback
 }
 
 func (n *Neural) Save(path string) error {
@@ -80,25 +94,65 @@ func (n *Neural) Save(path string) error {
 	return nil
 }
 
-func (n *Neural) Predict(in []types.Tensor) []float64 {
-	return n.network().Predict(types.Coerce[types.Tensor, float64](in))
+func (n *Neural) Predict(in []types.Byte) []float64 {
+	return n.network().Predict(types.Coerce[types.Byte, float64](in))
 }
 
-func Decode(prediction []float64) int {
-	return deep.ArgMax(prediction)
+// Embed returns the last hidden layer's activations as a feature vector of the drawing
+func (n *Neural) Embed(in []types.Byte) ([]float64, error) {
+	return n.network().Embed(types.Coerce[types.Byte, float64](in), -2)
+}
+
+// Saliency returns how much each pixel drove the prediction of digit, averaged over noisy copies of the drawing
+func (n *Neural) Saliency(in []types.Byte, digit int) ([]float64, error) {
+	return n.network().SmoothGrad(types.Coerce[types.Byte, float64](in), digit, 25, 0.1*255)
+}
+
+// pixelScaler scales 8-bit pixel intensities to (0,1), it is saved with the network
+// so that it takes raw pixels in training and prediction
+func pixelScaler(pixels int) *deep.Scaler {
+	s := deep.NewScaler(deep.ScaleMinMax)
+	s.Center, s.Scale = make([]float64, pixels), make([]float64, pixels)
+	for i := range s.Scale {
+		s.Scale[i] = 255
+	}
+	return s
 }
 
-func (n *Neural) Train(config TrainingConfig) error {
-	for i := range config.TrainingSet {
-		for j := range config.TrainingSet[i].Input {
-			config.TrainingSet[i].Input[j] = config.TrainingSet[i].Input[j] / 255
+// scalePixels sets the pixel scaler of networks saved without one,
+// which were trained on pixels scaled to (0,1)
+func scalePixels(n *deep.Neural) {
+	if n.Config.InputScaler == nil {
+		n.Config.InputScaler = pixelScaler(n.Config.Inputs)
+	}
+}
+
+// keepFrozen returns a func restoring the current frozen state of n's synapses,
+// so that temporary freezing does not unfreeze layers frozen beforehand
+func keepFrozen(n *deep.Neural) func() {
+	var synapses []*deep.Synapse
+	var frozen []bool
+	for _, l := range n.Layers {
+		for _, neuron := range l.Neurons {
+			for _, s := range neuron.In {
+				synapses, frozen = append(synapses, s), append(frozen, s.Frozen)
+			}
 		}
 	}
-	for i := range config.TestSet {
-		for j := range config.TestSet[i].Input {
-			config.TestSet[i].Input[j] = config.TestSet[i].Input[j] / 255
+	return func() {
+		for i, s := range synapses {
+			s.Frozen = frozen[i]
 		}
 	}
+}
+
+func Decode(prediction []float64) int {
+	return deep.ArgMax(prediction)
+}
+
+// Train trains the network until done or ctx is cancelled, in which case
+// the network keeps the weights of the last completed batch
+func (n *Neural) Train(ctx context.Context, config TrainingConfig) error {
 	fmt.Printf("training: %d, val: %d, test: %d\n", len(config.TrainingSet), len(config.TestSet), len(config.TestSet))
 
 	config.TestSet.Shuffle()
@@ -112,7 +166,7 @@ func (n *Neural) Train(config TrainingConfig) error {
 
 	fmt.Printf("expected: %v\n", expected)
 
-	fmt.Println(utils.String(types.Coerce[float64, types.Tensor](config.TestSet[0].Input)))
+	fmt.Println(utils.String(types.Coerce[float64, types.Byte](config.TestSet[0].Input)))
 
 	prediction := n.network().Predict(config.TestSet[0].Input)
 	predictedIndex := deep.ArgMax(prediction)
@@ -125,8 +179,30 @@ func (n *Neural) Train(config TrainingConfig) error {
 	}
 	fmt.Println(out)
 
+	if config.BalanceClasses {
+		defer func(weights []float64) { n.Config.ClassWeights = weights }(n.Config.ClassWeights)
+		n.Config.ClassWeights = config.TrainingSet.BalancedClassWeights()
+	}
+
+	if d, ok := config.Trainer.(interface{ SetDiagnostics(*training.Diagnostics) }); ok && config.Diagnostics != nil {
+		defer d.SetDiagnostics(nil)
+		d.SetDiagnostics(config.Diagnostics)
+	}
+
+	if c, ok := config.Trainer.(interface{ SetCallbacks(...training.Callback) }); ok && len(config.Callbacks) > 0 {
+		defer c.SetCallbacks()
+		c.SetCallbacks(config.Callbacks...)
+	}
+
+	if config.FreezeTrunk {
+		defer keepFrozen(n.network())()
+		n.network().FreezeTrunk()
+	}
+
 	trainStart := time.Now()
-	config.Trainer.Train(n.network(), config.TrainingSet, config.TestSet, config.Iterations)
+	if err := config.Trainer.TrainContext(ctx, n.network(), config.TrainingSet, config.TestSet, config.Iterations); err != nil {
+		return err
+	}
 	fmt.Printf("train time: %s/%s\n", time.Since(trainStart), time.Since(start))
 
 	return nil
@@ -1,12 +1,17 @@
 package server
 
 import (
+	"errors"
 	"github.com/labstack/echo/v4"
 	"github.com/patrikeh/go-deep/server/mnist"
+	"io/fs"
 )
 
 var neuralNetwork *mnist.Neural
 
+// generations holds recently retrained networks, served together once there are several
+var generations = &mnist.Generations{Max: maxGenerations}
+
 func Run(network *mnist.Neural, middlewares []echo.MiddlewareFunc, options ...func(e *echo.Echo)) error {
 	e := echo.New()
 
@@ -31,6 +36,14 @@ func Run(network *mnist.Neural, middlewares []echo.MiddlewareFunc, options ...fu
 		neuralNetwork = network
 	}
 
+	g, err := mnist.LoadGenerations(generationsFile, maxGenerations)
+	switch {
+	case err == nil:
+		generations = g
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
 	return e.Start(":1323")
 }
 
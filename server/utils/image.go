@@ -8,22 +8,6 @@ import (
 	"math"
 )
 
-func ImageToTensor(img image.Image) []types.Tensor {
-	bounds := img.Bounds()
-	width, height := bounds.Max.X, bounds.Max.Y
-
-	var tensors []types.Byte
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			c := img.At(x, y)
-			grayColor := color.GrayModel.Convert(c).(color.Gray)
-			tensors = append(tensors, types.Byte(grayColor.Y))
-		}
-	}
-
-	return types.BytesToTensor(tensors)
-}
-
 func ImageToBytes(img image.Image) []types.Byte {
 	bounds := img.Bounds()
 	width, height := bounds.Max.X, bounds.Max.Y
@@ -48,6 +32,31 @@ func TensorToImage(tensor []types.Tensor) image.Image {
 	return img
 }
 
+// SaliencyToImage renders a 28x28 saliency map as a heatmap, red for
+// positive and blue for negative attributions, scaled by the largest magnitude
+func SaliencyToImage(saliency []float64) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 28, 28))
+	var scale float64
+	for _, v := range saliency {
+		scale = math.Max(scale, math.Abs(v))
+	}
+	for i, v := range saliency {
+		x, y := i%28, i/28
+		var intensity uint8
+		if scale > 0 {
+			intensity = uint8(math.Round(255 * math.Abs(v) / scale))
+		}
+		c := color.RGBA{A: 255}
+		if v > 0 {
+			c.R = intensity
+		} else {
+			c.B = intensity
+		}
+		img.SetRGBA(x, y, c)
+	}
+	return img
+}
+
 func RotateImage(img image.Image, degrees float64) image.Image {
 	bounds := img.Bounds()
 	width, height := bounds.Dx(), bounds.Dy()
@@ -142,6 +151,7 @@ func (c SyntheticConfig) Synthesize(data []training.Example) SyntheticData {
 				training.Example{
 					Input:    types.Coerce[types.Byte, float64](ImageToBytes(rotated)),
 					Response: data[i].Response,
+					Weight:   data[i].Weight,
 				},
 			)
 
@@ -153,6 +163,7 @@ func (c SyntheticConfig) Synthesize(data []training.Example) SyntheticData {
 				training.Example{
 					Input:    types.Coerce[types.Byte, float64](ImageToBytes(translated)),
 					Response: data[i].Response,
+					Weight:   data[i].Weight,
 				},
 			)
 		}
@@ -163,6 +174,7 @@ func (c SyntheticConfig) Synthesize(data []training.Example) SyntheticData {
 				training.Example{
 					Input:    types.Coerce[types.Byte, float64](ImageToBytes(zoomed)),
 					Response: data[i].Response,
+					Weight:   data[i].Weight,
 				},
 			)
 		}
@@ -170,7 +182,3 @@ func (c SyntheticConfig) Synthesize(data []training.Example) SyntheticData {
 
 	return syntheticData
 }
-
-func normalize(value uint8) float64 {
-	return float64(value) / 255.0
-}
@@ -0,0 +1,111 @@
+package deep
+
+import "fmt"
+
+// CSR is a sparse matrix in compressed sparse row format.
+// Row i holds the weights of neuron i, columns index its incoming synapses.
+type CSR struct {
+	Rows, Cols int
+	RowPtr     []int
+	ColIdx     []int
+	Values     []float64
+}
+
+// NewCSR compresses the unpruned synapses of l
+func NewCSR(l *Layer) *CSR {
+	m := &CSR{Rows: len(l.Neurons), RowPtr: make([]int, 0, len(l.Neurons)+1)}
+	m.RowPtr = append(m.RowPtr, 0)
+	for _, n := range l.Neurons {
+		m.Cols = max(m.Cols, len(n.In))
+		for k, s := range n.In {
+			if s.Pruned {
+				continue
+			}
+			m.ColIdx = append(m.ColIdx, k)
+			m.Values = append(m.Values, s.Weight)
+		}
+		m.RowPtr = append(m.RowPtr, len(m.Values))
+	}
+	return m
+}
+
+// MulVec computes y = Mx
+func (m *CSR) MulVec(x, y []float64) {
+	for i := 0; i < m.Rows; i++ {
+		var sum float64
+		for p := m.RowPtr[i]; p < m.RowPtr[i+1]; p++ {
+			sum += m.Values[p] * x[m.ColIdx[p]]
+		}
+		y[i] = sum
+	}
+}
+
+// NNZ is the number of stored (unpruned) weights
+func (m *CSR) NNZ() int {
+	return len(m.Values)
+}
+
+// SparseNeural is an inference-only copy of a network storing
+// each layer as a sparse matrix, which is faster than Neural at high sparsity
+type SparseNeural struct {
+	Config *Config
+	Layers []*CSR
+	A      []ActivationType
+}
+
+// Sparse compresses n into a SparseNeural
+func (n *Neural) Sparse() *SparseNeural {
+	s := &SparseNeural{
+		Config: n.Config,
+		Layers: make([]*CSR, len(n.Layers)),
+		A:      make([]ActivationType, len(n.Layers)),
+	}
+	for i, l := range n.Layers {
+		s.Layers[i] = NewCSR(l)
+		s.A[i] = l.A
+	}
+	return s
+}
+
+// Forward computes a forward pass and returns the output layer values
+func (s *SparseNeural) Forward(input []float64) ([]float64, error) {
+	if len(input) != s.Config.Inputs {
+		return nil, fmt.Errorf("Invalid input dimension - expected: %d got: %d", s.Config.Inputs, len(input))
+	}
+	x := s.Config.InputScaler.Transform(input)
+	for i, m := range s.Layers {
+		if m.Cols > len(x) {
+			// bias synapses are the trailing inputs of each neuron
+			x = append(x[:len(x):len(x)], 1)
+		}
+		y := make([]float64, m.Rows)
+		m.MulVec(x, y)
+		if i == len(s.Layers)-1 && len(s.Config.Heads) > 0 {
+			for _, sp := range s.Config.spans() {
+				activate(y[sp.start:sp.end], sp.a)
+			}
+		} else {
+			activate(y, s.A[i])
+		}
+		x = y
+	}
+	return x, nil
+}
+
+// activate applies the activation to y in place
+func activate(y []float64, a ActivationType) {
+	if a == ActivationSoftmax {
+		copy(y, Softmax(y))
+		return
+	}
+	act := GetActivation(a)
+	for j := range y {
+		y[j] = act.F(y[j])
+	}
+}
+
+// Predict computes a forward pass and returns a prediction
+func (s *SparseNeural) Predict(input []float64) []float64 {
+	out, _ := s.Forward(input)
+	return s.Config.TargetScaler.InverseTransform(out)
+}
@@ -0,0 +1,75 @@
+package deep
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SparsePredict(t *testing.T) {
+	rand.Seed(0)
+	for _, mode := range []Mode{ModeMultiClass, ModeRegression, ModeBinary} {
+		n := NewNeural(&Config{
+			Inputs:     5,
+			Layout:     []int{6, 4, 3},
+			Activation: ActivationTanh,
+			Mode:       mode,
+			Weight:     NewNormal(1, 0),
+			Bias:       true,
+		})
+		n.Prune(0.6, PruneGlobal)
+		s := n.Sparse()
+
+		input := []float64{0.1, -0.4, 0.3, 0.9, -1}
+		expected, actual := n.Predict(input), s.Predict(input)
+		for i := range expected {
+			assert.InDelta(t, expected[i], actual[i], 1e-12)
+		}
+	}
+}
+
+func Test_SparseDimension(t *testing.T) {
+	n := NewNeural(&Config{Inputs: 2, Layout: []int{2, 1}})
+	_, err := n.Sparse().Forward([]float64{1})
+	assert.Error(t, err)
+}
+
+func benchmarkNetwork(sparsity float64) *Neural {
+	rand.Seed(0)
+	n := NewNeural(&Config{
+		Inputs:     784,
+		Layout:     []int{300, 100, 10},
+		Activation: ActivationReLU,
+		Mode:       ModeMultiClass,
+		Weight:     NewNormal(0.1, 0),
+		Bias:       true,
+	})
+	n.Prune(sparsity, PruneGlobal)
+	return n
+}
+
+func benchmarkInput() []float64 {
+	input := make([]float64, 784)
+	for i := range input {
+		input[i] = rand.Float64()
+	}
+	return input
+}
+
+func Benchmark_DensePredict(b *testing.B) {
+	n, input := benchmarkNetwork(0.95), benchmarkInput()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.Predict(input)
+	}
+}
+
+func Benchmark_SparsePredict(b *testing.B) {
+	n, input := benchmarkNetwork(0.95), benchmarkInput()
+	s := n.Sparse()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Predict(input)
+	}
+}
@@ -0,0 +1,120 @@
+package deep
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// LayerSummary describes a layer of a network
+type LayerSummary struct {
+	Kind       string
+	Inputs     int
+	Neurons    int
+	Activation string
+	Bias       bool
+	Params     int
+	Trainable  int
+}
+
+// Summary describes the topology and parameter counts of a network
+type Summary struct {
+	Inputs    int
+	Mode      string
+	Loss      string
+	Layers    []LayerSummary
+	Params    int
+	Trainable int
+}
+
+// Summary returns a summary of n's layers
+func (n *Neural) Summary() Summary {
+	s := Summary{Inputs: n.Config.Inputs, Mode: n.Config.Mode.String(), Loss: n.Config.Loss.String()}
+	if len(n.Config.Heads) > 0 {
+		var heads []string
+		for _, h := range n.Config.OutputHeads() {
+			heads = append(heads, fmt.Sprintf("%s: %s", h.Name, h.Loss))
+		}
+		s.Mode, s.Loss = "Heads", strings.Join(heads, ", ")
+	}
+	for i, l := range n.Layers {
+		ls := LayerSummary{
+			Kind:       "Dense",
+			Inputs:     n.fanIn(i),
+			Neurons:    len(l.Neurons),
+			Activation: l.A.String(),
+			Bias:       n.Config.hasBias(i),
+		}
+		if i == len(n.Layers)-1 {
+			ls.Kind = "Output"
+			if len(l.spans) > 0 {
+				var acts []string
+				for _, sp := range l.spans {
+					acts = append(acts, sp.a.String())
+				}
+				ls.Activation = strings.Join(acts, "/")
+			}
+		}
+		for _, neuron := range l.Neurons {
+			for _, syn := range neuron.In {
+				ls.Params++
+				if syn.Trainable() {
+					ls.Trainable++
+				}
+			}
+		}
+		s.Params += ls.Params
+		s.Trainable += ls.Trainable
+		s.Layers = append(s.Layers, ls)
+	}
+	return s
+}
+
+func (s Summary) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "Layer\tKind\tShape\tActivation\tParams\tTrainable\t\n")
+	fmt.Fprintf(w, "-\tInput\t%d\t\t\t\t\n", s.Inputs)
+	for i, l := range s.Layers {
+		fmt.Fprintf(w, "%d\t%s\t%d → %d\t%s\t%d\t%d\t\n", i, l.Kind, l.Inputs, l.Neurons, l.Activation, l.Params, l.Trainable)
+	}
+	w.Flush()
+	fmt.Fprintf(&b, "Mode: %s, loss: %s\n", s.Mode, s.Loss)
+	fmt.Fprintf(&b, "Total params: %d, trainable: %d\n", s.Params, s.Trainable)
+	return b.String()
+}
+
+// JSON returns the summary as JSON
+func (s Summary) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// DOT returns the layer graph of n in Graphviz DOT format
+func (n *Neural) DOT() string {
+	s := n.Summary()
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph network {\n\trankdir=LR;\n\tnode [shape=record];\n")
+	fmt.Fprintf(&b, "\tinput [label=\"Input|%d\"];\n", s.Inputs)
+	prev := "input"
+	for i, l := range s.Layers {
+		node := fmt.Sprintf("layer%d", i)
+		label := fmt.Sprintf("%s %d|%d neurons|%s", l.Kind, i, l.Neurons, l.Activation)
+		if i == len(s.Layers)-1 && len(n.Config.Heads) > 0 {
+			label = fmt.Sprintf("%s|%s", label, n.headsLabel())
+		}
+		fmt.Fprintf(&b, "\t%s [label=\"%s\"];\n", node, label)
+		fmt.Fprintf(&b, "\t%s -> %s [label=\"%d params\"];\n", prev, node, l.Params)
+		prev = node
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (n *Neural) headsLabel() string {
+	var heads []string
+	for _, h := range n.Config.OutputHeads() {
+		heads = append(heads, fmt.Sprintf("%s: %d %s", h.Name, h.Outputs, h.Mode))
+	}
+	return "{" + strings.Join(heads, "|") + "}"
+}
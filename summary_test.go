@@ -0,0 +1,48 @@
+package deep
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Summary(t *testing.T) {
+	n := NewNeural(&Config{
+		Inputs:     3,
+		Layout:     []int{4, 2},
+		Activation: ActivationReLU,
+		Mode:       ModeMultiClass,
+		Bias:       true,
+	})
+	n.Freeze(0)
+
+	s := n.Summary()
+	assert.Equal(t, []LayerSummary{
+		{Kind: "Dense", Inputs: 3, Neurons: 4, Activation: "ReLU", Bias: true, Params: 16, Trainable: 0},
+		{Kind: "Output", Inputs: 4, Neurons: 2, Activation: "Softmax", Bias: true, Params: 10, Trainable: 10},
+	}, s.Layers)
+	assert.Equal(t, 26, s.Params)
+	assert.Equal(t, 10, s.Trainable)
+	assert.Equal(t, n.NumWeights(), s.Params)
+
+	text := s.String()
+	assert.Contains(t, text, "Output   4 → 2   Softmax      10       10")
+	assert.Contains(t, text, "Mode: MultiClass, loss: CE")
+	assert.Contains(t, text, "Total params: 26, trainable: 10")
+
+	data, err := s.JSON()
+	assert.NoError(t, err)
+	var decoded Summary
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, s, decoded)
+}
+
+func Test_DOT(t *testing.T) {
+	dot := headsNetwork().DOT()
+	assert.Contains(t, dot, "digraph network {")
+	assert.Contains(t, dot, "input [label=\"Input|3\"];")
+	assert.Contains(t, dot, "input -> layer0 [label=\"16 params\"];")
+	assert.Contains(t, dot, "layer1 [label=\"Output 1|6 neurons|Softmax/Linear/Sigmoid|{digit")
+	assert.Contains(t, dot, "{digit: 3 MultiClass|thickness: 1 Regression|labels: 2 MultiLabel}")
+}
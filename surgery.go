@@ -0,0 +1,219 @@
+package deep
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+)
+
+// InsertLayer inserts a hidden layer before layer i, initialized to the
+// identity with zero biases. It preserves the network function exactly for
+// linear activations and ReLU on non-negative inputs, approximately otherwise.
+func (n *Neural) InsertLayer(i int) error {
+	if i < 0 || i >= len(n.Layers) {
+		return fmt.Errorf("Invalid layer - expected 0 to %d, got: %d", len(n.Layers)-1, i)
+	}
+	c := n.copyConfig()
+	width := n.fanIn(i)
+	c.Layout = slices.Insert(c.Layout, i, width)
+
+	layer := make([][]Synapse, width)
+	for j := range layer {
+		layer[j] = make([]Synapse, width)
+		layer[j][j].Weight = 1
+		if c.hasBias(i) {
+			layer[j] = append(layer[j], Synapse{IsBias: true})
+		}
+	}
+	return n.rebuild(c, slices.Insert(n.synapses(), i, layer))
+}
+
+// RemoveLayer removes hidden layer i, folding its weights into the next layer
+// as if its activation were linear. This undoes InsertLayer exactly. The
+// folded layer loses pruning and freezing, and drops the removed layer's
+// biases if it has no bias of its own.
+func (n *Neural) RemoveLayer(i int) error {
+	if i < 0 || i >= len(n.Layers)-1 {
+		return fmt.Errorf("Invalid layer - expected hidden layer 0 to %d, got: %d", len(n.Layers)-2, i)
+	}
+	c := n.copyConfig()
+	c.Layout = slices.Delete(c.Layout, i, i+1)
+
+	synapses := n.synapses()
+	fanIn := n.fanIn(i)
+	removed, next := synapses[i], synapses[i+1]
+	folded := make([][]Synapse, len(next))
+	for q, row := range next {
+		folded[q] = make([]Synapse, fanIn)
+		for r := range folded[q] {
+			for j := range removed {
+				folded[q][r].Weight += row[j].Weight * removed[j][r].Weight
+			}
+		}
+		if c.hasBias(i) {
+			bias := Synapse{IsBias: true, Weight: row[len(row)-1].Weight}
+			if n.Config.hasBias(i) {
+				for j := range removed {
+					bias.Weight += row[j].Weight * removed[j][fanIn].Weight
+				}
+			}
+			folded[q] = append(folded[q], bias)
+		}
+	}
+	synapses[i+1] = folded
+	return n.rebuild(c, slices.Delete(synapses, i, i+1))
+}
+
+// Resize changes the number of neurons in hidden layer i. Growing copies
+// randomly chosen neurons and splits their outgoing weights among the copies
+// (Net2Net), which preserves the network function. Shrinking keeps the
+// neurons with the largest outgoing weights.
+func (n *Neural) Resize(i, size int) error {
+	if i < 0 || i >= len(n.Layers)-1 {
+		return fmt.Errorf("Invalid layer - expected hidden layer 0 to %d, got: %d", len(n.Layers)-2, i)
+	}
+	if size <= 0 {
+		return fmt.Errorf("Invalid size - expected positive, got: %d", size)
+	}
+	c := n.copyConfig()
+	c.Layout[i] = size
+
+	synapses := n.synapses()
+	source := n.resizeSources(i, size)
+	copies := make([]float64, len(n.Layers[i].Neurons))
+	for _, g := range source {
+		copies[g]++
+	}
+
+	layer := make([][]Synapse, size)
+	for j, g := range source {
+		layer[j] = slices.Clone(synapses[i][g])
+	}
+	next := make([][]Synapse, len(synapses[i+1]))
+	for q, row := range synapses[i+1] {
+		next[q] = make([]Synapse, 0, len(row)-len(copies)+size)
+		for _, g := range source {
+			s := row[g]
+			s.Weight /= copies[g]
+			next[q] = append(next[q], s)
+		}
+		next[q] = append(next[q], row[len(copies):]...)
+	}
+	synapses[i], synapses[i+1] = layer, next
+	return n.rebuild(c, synapses)
+}
+
+// resizeSources maps each neuron of the resized layer i to the neuron it is copied from
+func (n *Neural) resizeSources(i, size int) []int {
+	width := len(n.Layers[i].Neurons)
+	if size >= width {
+		source := make([]int, size)
+		for j := range source {
+			if j < width {
+				source[j] = j
+			} else {
+				source[j] = rand.Intn(width)
+			}
+		}
+		return source
+	}
+
+	magnitude := make([]float64, width)
+	for _, neuron := range n.Layers[i+1].Neurons {
+		for j := 0; j < width; j++ {
+			magnitude[j] += math.Abs(neuron.In[j].Weight)
+		}
+	}
+	source := make([]int, width)
+	for j := range source {
+		source[j] = j
+	}
+	sort.SliceStable(source, func(a, b int) bool { return magnitude[source[a]] > magnitude[source[b]] })
+	source = source[:size]
+	sort.Ints(source)
+	return source
+}
+
+// ResizeInputs changes the number of inputs. Added inputs get zero weights,
+// so the network function is preserved, removed inputs are the trailing ones.
+// Added inputs are not scaled by the InputScaler.
+func (n *Neural) ResizeInputs(inputs int) error {
+	if inputs <= 0 {
+		return fmt.Errorf("Invalid inputs - expected positive, got: %d", inputs)
+	}
+	c := n.copyConfig()
+	c.Inputs = inputs
+	c.InputScaler = n.Config.InputScaler.resize(inputs)
+
+	synapses := n.synapses()
+	for j, row := range synapses[0] {
+		weights := make([]Synapse, inputs)
+		copy(weights, row[:min(inputs, n.Config.Inputs)])
+		synapses[0][j] = append(weights, row[n.Config.Inputs:]...)
+	}
+	return n.rebuild(c, synapses)
+}
+
+// fanIn is the number of non-bias inputs of layer i
+func (n *Neural) fanIn(i int) int {
+	if i == 0 {
+		return n.Config.Inputs
+	}
+	return len(n.Layers[i-1].Neurons)
+}
+
+func (n *Neural) copyConfig() *Config {
+	c := *n.Config
+	c.Layout = slices.Clone(n.Config.Layout)
+	c.Heads = slices.Clone(n.Config.Heads)
+	c.InputScaler = n.Config.InputScaler.clone()
+	c.TargetScaler = n.Config.TargetScaler.clone()
+	return &c
+}
+
+// synapses returns copies of all synapses, indexed like Weights
+func (n *Neural) synapses() [][][]Synapse {
+	synapses := make([][][]Synapse, len(n.Layers))
+	for i, l := range n.Layers {
+		synapses[i] = make([][]Synapse, len(l.Neurons))
+		for j, neuron := range l.Neurons {
+			synapses[i][j] = make([]Synapse, len(neuron.In))
+			for k, s := range neuron.In {
+				synapses[i][j][k] = *s
+			}
+		}
+	}
+	return synapses
+}
+
+// rebuild replaces n by a network for c with the given synapse weights and flags
+func (n *Neural) rebuild(c *Config, synapses [][][]Synapse) error {
+	r, err := NewNeuralE(c)
+	if err != nil {
+		return err
+	}
+	weights := make([][][]float64, len(synapses))
+	for i := range synapses {
+		weights[i] = make([][]float64, len(synapses[i]))
+		for j := range synapses[i] {
+			weights[i][j] = make([]float64, len(synapses[i][j]))
+			for k, s := range synapses[i][j] {
+				weights[i][j][k] = s.Weight
+			}
+		}
+	}
+	if err := r.ApplyWeightsE(weights); err != nil {
+		return err
+	}
+	for i, l := range r.Layers {
+		for j, neuron := range l.Neurons {
+			for k, s := range neuron.In {
+				s.Pruned, s.Frozen = synapses[i][j][k].Pruned, synapses[i][j][k].Frozen
+			}
+		}
+	}
+	*n = *r
+	return nil
+}
@@ -0,0 +1,97 @@
+package deep
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func surgeryNetwork(act ActivationType, mode Mode) *Neural {
+	rand.Seed(0)
+	return NewNeural(&Config{
+		Inputs:     3,
+		Layout:     []int{4, 5, 2},
+		Activation: act,
+		Mode:       mode,
+		Weight:     NewNormal(1, 0),
+		Bias:       true,
+	})
+}
+
+var surgeryInputs = [][]float64{{0, 0, 0}, {1, 0.5, 2}, {0.3, 0.1, 0.7}}
+
+func assertSamePredictions(t *testing.T, want [][]float64, n *Neural) {
+	for i, x := range surgeryInputs {
+		assert.InDeltaSlice(t, want[i], n.Predict(x), 1e-9)
+	}
+}
+
+func predictions(n *Neural) [][]float64 {
+	var out [][]float64
+	for _, x := range surgeryInputs {
+		out = append(out, n.Predict(x))
+	}
+	return out
+}
+
+func Test_InsertRemoveLayer(t *testing.T) {
+	for _, mode := range []Mode{ModeMultiClass, ModeRegression} {
+		n := surgeryNetwork(ActivationReLU, mode)
+		want := predictions(n)
+
+		for _, i := range []int{0, 2} {
+			assert.NoError(t, n.InsertLayer(i))
+			assertSamePredictions(t, want, n)
+		}
+		assert.Equal(t, []int{3, 4, 4, 5, 2}, n.Config.Layout)
+		assert.Len(t, n.Layers, 5)
+		assert.Len(t, n.Layers[3].Neurons[0].In, 5)
+		assert.Len(t, n.Layers[4].Neurons[0].In, 5+map[Mode]int{ModeMultiClass: 1}[mode])
+
+		assert.NoError(t, n.RemoveLayer(2))
+		assert.NoError(t, n.RemoveLayer(0))
+		assert.Equal(t, []int{4, 5, 2}, n.Config.Layout)
+		assertSamePredictions(t, want, n)
+	}
+
+	n := surgeryNetwork(ActivationReLU, ModeMultiClass)
+	assert.Error(t, n.InsertLayer(3))
+	assert.Error(t, n.RemoveLayer(2))
+}
+
+func Test_Resize(t *testing.T) {
+	n := surgeryNetwork(ActivationTanh, ModeMultiClass)
+	n.Freeze(0)
+	want := predictions(n)
+
+	assert.NoError(t, n.Resize(0, 9))
+	assert.Equal(t, []int{9, 5, 2}, n.Config.Layout)
+	assert.Len(t, n.Layers[1].Neurons[0].In, 10)
+	assert.True(t, n.IsFrozen(0))
+	assertSamePredictions(t, want, n)
+
+	assert.NoError(t, n.Resize(1, 3))
+	assert.Equal(t, []int{9, 3, 2}, n.Config.Layout)
+	assert.Len(t, n.Layers[2].Neurons[0].In, 4)
+	assert.Len(t, n.Predict(surgeryInputs[1]), 2)
+
+	assert.Error(t, n.Resize(2, 4))
+	assert.Error(t, n.Resize(0, 0))
+}
+
+func Test_ResizeInputs(t *testing.T) {
+	n := surgeryNetwork(ActivationSigmoid, ModeBinary)
+	want := predictions(n)
+
+	assert.NoError(t, n.ResizeInputs(5))
+	assert.Equal(t, 5, n.Config.Inputs)
+	for i, x := range surgeryInputs {
+		assert.InDeltaSlice(t, want[i], n.Predict(append(x, 3, -1)), 1e-9)
+	}
+
+	assert.NoError(t, n.ResizeInputs(2))
+	assert.Len(t, n.Layers[0].Neurons[0].In, 3)
+	assert.Len(t, n.Predict([]float64{1, 2}), 2)
+	assert.Error(t, n.ResizeInputs(0))
+}
@@ -0,0 +1,172 @@
+package training
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	deep "github.com/patrikeh/go-deep"
+)
+
+// Attack perturbs an example's input to increase the loss of n
+type Attack interface {
+	Perturb(n *deep.Neural, e Example) (Example, error)
+}
+
+// RandomAttack is implemented by attacks that draw random numbers. Checkpointed
+// runs pass them a source seeded per example, so that resumed runs are exact.
+type RandomAttack interface {
+	Attack
+	PerturbRand(n *deep.Neural, e Example, r *rand.Rand) (Example, error)
+}
+
+// FGSM is the fast gradient sign method, moving each input by Epsilon
+// in the direction of the sign of the loss gradient
+type FGSM struct {
+	Epsilon float64
+	// Clip limits the perturbed input to [Min, Max]
+	Clip     bool
+	Min, Max float64
+}
+
+// Perturb returns a copy of e with an adversarial input
+func (a FGSM) Perturb(n *deep.Neural, e Example) (Example, error) {
+	if a.Clip && a.Min > a.Max {
+		return e, fmt.Errorf("Invalid clip range - min %v exceeds max %v", a.Min, a.Max)
+	}
+	gradient, err := InputLossGradient(n, e)
+	if err != nil {
+		return e, err
+	}
+	input := make([]float64, len(e.Input))
+	for i, x := range e.Input {
+		input[i] = clip(x+a.Epsilon*deep.Sgn(gradient[i]), a.Clip, a.Min, a.Max)
+	}
+	e.Input = input
+	return e, nil
+}
+
+// PGD is projected gradient descent, taking Steps signed gradient steps of
+// size Alpha, each projected back onto the Epsilon ball around the input.
+// Alpha defaults to 2.5·Epsilon/Steps.
+type PGD struct {
+	Epsilon, Alpha float64
+	Steps          int
+	// RandomStart starts from a uniformly random point in the Epsilon ball
+	RandomStart bool
+	// Clip limits the perturbed input to [Min, Max]
+	Clip     bool
+	Min, Max float64
+}
+
+// Perturb returns a copy of e with an adversarial input
+func (a PGD) Perturb(n *deep.Neural, e Example) (Example, error) {
+	return a.PerturbRand(n, e, nil)
+}
+
+// PerturbRand is Perturb with the random start drawn from r, or from the global source if r is nil
+func (a PGD) PerturbRand(n *deep.Neural, e Example, r *rand.Rand) (Example, error) {
+	if a.Clip && a.Min > a.Max {
+		return e, fmt.Errorf("Invalid clip range - min %v exceeds max %v", a.Min, a.Max)
+	}
+	uniform := rand.Float64
+	if r != nil {
+		uniform = r.Float64
+	}
+	adversarial := e
+	adversarial.Input = make([]float64, len(e.Input))
+	for i, x := range e.Input {
+		if a.RandomStart {
+			x += (uniform()*2 - 1) * a.Epsilon
+		}
+		adversarial.Input[i] = clip(x, a.Clip, a.Min, a.Max)
+	}
+	steps := iparam(a.Steps, 1)
+	for s := 0; s < steps; s++ {
+		gradient, err := InputLossGradient(n, adversarial)
+		if err != nil {
+			return e, err
+		}
+		for i, x := range adversarial.Input {
+			x += fparam(a.Alpha, 2.5*a.Epsilon/float64(steps)) * deep.Sgn(gradient[i])
+			x = math.Max(e.Input[i]-a.Epsilon, math.Min(e.Input[i]+a.Epsilon, x))
+			adversarial.Input[i] = clip(x, a.Clip, a.Min, a.Max)
+		}
+	}
+	return adversarial, nil
+}
+
+// InputLossGradient returns the gradient of the loss of n on e with respect to the input
+func InputLossGradient(n *deep.Neural, e Example) ([]float64, error) {
+	if err := n.Forward(e.Input); err != nil {
+		return nil, err
+	}
+	deltas := make([]float64, len(n.Layers[len(n.Layers)-1].Neurons))
+	outputDeltas(n, e.Response, 1, deltas)
+	return n.BackpropInput(deltas), nil
+}
+
+// perturb applies attack to e, drawing random numbers from r if it is a RandomAttack and r is set
+func perturb(n *deep.Neural, attack Attack, e Example, r *rand.Rand) (Example, error) {
+	if a, ok := attack.(RandomAttack); ok && r != nil {
+		return a.PerturbRand(n, e, r)
+	}
+	return attack.Perturb(n, e)
+}
+
+// Perturb returns adversarial copies of examples
+func Perturb(n *deep.Neural, examples Examples, attack Attack) (Examples, error) {
+	perturbed := make(Examples, len(examples))
+	for i, e := range examples {
+		p, err := attack.Perturb(n, e)
+		if err != nil {
+			return nil, err
+		}
+		perturbed[i] = p
+	}
+	return perturbed, nil
+}
+
+// RobustnessReport holds the accuracy of a network under attack at increasing strengths
+type RobustnessReport struct {
+	Epsilons []float64
+	Accuracy []float64
+}
+
+func (r RobustnessReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-10s%s\n", "Epsilon", "Accuracy")
+	for i, eps := range r.Epsilons {
+		fmt.Fprintf(&b, "%-10.3f%.4f\n", eps, r.Accuracy[i])
+	}
+	return b.String()
+}
+
+// Robustness measures the accuracy of n on examples perturbed by the attack
+// returned for each epsilon. Predictions count as correct if they fall in the
+// response's class.
+func Robustness(n *deep.Neural, examples Examples, epsilons []float64, attack func(epsilon float64) Attack) (RobustnessReport, error) {
+	report := RobustnessReport{Epsilons: epsilons, Accuracy: make([]float64, len(epsilons))}
+	for i, eps := range epsilons {
+		perturbed, err := Perturb(n, examples, attack(eps))
+		if err != nil {
+			return report, err
+		}
+		var correct int
+		for _, e := range perturbed {
+			if class(n.Predict(e.Input)) == class(e.Response) {
+				correct++
+			}
+		}
+		report.Accuracy[i] = float64(correct) / float64(len(perturbed))
+	}
+	return report, nil
+}
+
+func clip(x float64, enabled bool, lo, hi float64) float64 {
+	if !enabled {
+		return x
+	}
+	return math.Max(lo, math.Min(hi, x))
+}
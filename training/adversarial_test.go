@@ -0,0 +1,119 @@
+package training
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func adversarialNetwork() *deep.Neural {
+	rand.Seed(0)
+	n := deep.NewNeural(&deep.Config{
+		Inputs:     2,
+		Layout:     []int{6, 2},
+		Activation: deep.ActivationTanh,
+		Mode:       deep.ModeMultiClass,
+		Weight:     deep.NewNormal(0.5, 0),
+		Bias:       true,
+	})
+	NewTrainer(NewAdam(0.02, 0, 0, 0), 0).Train(n, quadrants(200), nil, 30)
+	return n
+}
+
+// quadrants are points in [0, 1]² classified by whether x > y
+func quadrants(size int) Examples {
+	examples := make(Examples, size)
+	for i := range examples {
+		x, y := rand.Float64(), rand.Float64()
+		response := []float64{1, 0}
+		if x > y {
+			response = []float64{0, 1}
+		}
+		examples[i] = Example{Input: []float64{x, y}, Response: response}
+	}
+	return examples
+}
+
+func Test_InputLossGradient(t *testing.T) {
+	n := adversarialNetwork()
+	e := Example{Input: []float64{0.3, 0.6}, Response: []float64{0, 1}}
+
+	gradient, err := InputLossGradient(n, e)
+	assert.NoError(t, err)
+
+	const epsilon = 1e-6
+	for i := range e.Input {
+		plus, minus := e, e
+		plus.Input, minus.Input = []float64{0.3, 0.6}, []float64{0.3, 0.6}
+		plus.Input[i] += epsilon
+		minus.Input[i] -= epsilon
+		numerical := (objective(n, Examples{plus}) - objective(n, Examples{minus})) / (2 * epsilon)
+		assert.InDelta(t, numerical, gradient[i], 1e-6)
+	}
+}
+
+func Test_Attacks(t *testing.T) {
+	n := adversarialNetwork()
+	e := Example{Input: []float64{0.3, 0.6}, Response: []float64{1, 0}}
+	loss := objective(n, Examples{e})
+
+	for _, attack := range []Attack{
+		FGSM{Epsilon: 0.1, Clip: true, Min: 0, Max: 1},
+		PGD{Epsilon: 0.1, Steps: 10, RandomStart: true, Clip: true, Min: 0, Max: 1},
+	} {
+		adversarial, err := attack.Perturb(n, e)
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{0.3, 0.6}, e.Input)
+		assert.Equal(t, e.Response, adversarial.Response)
+		for i, x := range adversarial.Input {
+			assert.LessOrEqual(t, math.Abs(x-e.Input[i]), 0.1+1e-12)
+		}
+		assert.Greater(t, objective(n, Examples{adversarial}), loss)
+	}
+
+	clipped, _ := FGSM{Epsilon: 0.5, Clip: true, Min: 0, Max: 1}.Perturb(n, e)
+	for _, x := range clipped.Input {
+		assert.True(t, x >= 0 && x <= 1)
+	}
+
+	zero, err := PGD{Epsilon: 0.5, Clip: true}.Perturb(n, e)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 0}, zero.Input)
+
+	_, err = FGSM{Epsilon: 0.1, Clip: true, Min: 1, Max: 0}.Perturb(n, e)
+	assert.EqualError(t, err, "Invalid clip range - min 1 exceeds max 0")
+}
+
+func Test_Robustness(t *testing.T) {
+	n := adversarialNetwork()
+	examples := quadrants(100)
+
+	report, err := Robustness(n, examples, []float64{0, 0.05, 0.2}, func(eps float64) Attack {
+		return FGSM{Epsilon: eps}
+	})
+	assert.NoError(t, err)
+	assert.InDelta(t, accuracy(n, examples, 0, 2), report.Accuracy[0], 1e-12)
+	assert.Greater(t, report.Accuracy[0], report.Accuracy[2])
+	assert.Contains(t, report.String(), "0.200")
+}
+
+func Test_AdversarialTraining(t *testing.T) {
+	rand.Seed(0)
+	n := deep.NewNeural(&deep.Config{
+		Inputs:     2,
+		Layout:     []int{6, 2},
+		Activation: deep.ActivationTanh,
+		Mode:       deep.ModeMultiClass,
+		Weight:     deep.NewNormal(0.5, 0),
+		Bias:       true,
+	})
+	examples := quadrants(200)
+
+	trainer := NewBatchTrainer(NewAdam(0.02, 0, 0, 0), 0, 10, 2)
+	trainer.SetAdversarial(PGD{Epsilon: 0.02, Steps: 3}, 0.5)
+	assert.NoError(t, trainer.Train(n, examples, nil, 50))
+	assert.Greater(t, accuracy(n, examples, 0, 2), 0.9)
+}
@@ -1,6 +1,10 @@
 package training
 
 import (
+	"cmp"
+	"context"
+	"math"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -10,24 +14,34 @@ import (
 // BatchTrainer implements parallelized batch training
 type BatchTrainer struct {
 	*internalb
+	monitor
+	diagnosing
+	hooks
+	checkpointing
 	verbosity   int
 	batchSize   int
 	parallelism int
 	solver      Solver
 	printer     *StatsPrinter
+	attack      Attack
+	adversarial float64
 }
 
 type internalb struct {
+	stepper
 	deltas            [][][]float64
 	partialDeltas     [][][][]float64
 	accumulatedDeltas [][][]float64
 	moments           [][][]float64
+	errs              []error
+	losses            []float64
 }
 
 func newBatchTraining(layers []*deep.Layer, parallelism int) *internalb {
 	deltas := make([][][]float64, parallelism)
 	partialDeltas := make([][][][]float64, parallelism)
 	accumulatedDeltas := make([][][]float64, len(layers))
+	var weights int
 	for w := 0; w < parallelism; w++ {
 		deltas[w] = make([][]float64, len(layers))
 		partialDeltas[w] = make([][][]float64, len(layers))
@@ -39,13 +53,19 @@ func newBatchTraining(layers []*deep.Layer, parallelism int) *internalb {
 			for j, n := range l.Neurons {
 				partialDeltas[w][i][j] = make([]float64, len(n.In))
 				accumulatedDeltas[i][j] = make([]float64, len(n.In))
+				if w == 0 {
+					weights += len(n.In)
+				}
 			}
 		}
 	}
 	return &internalb{
+		stepper:           newStepper(weights),
 		deltas:            deltas,
 		partialDeltas:     partialDeltas,
 		accumulatedDeltas: accumulatedDeltas,
+		errs:              make([]error, parallelism),
+		losses:            make([]float64, parallelism),
 	}
 }
 
@@ -60,47 +80,123 @@ func NewBatchTrainer(solver Solver, verbosity, batchSize, parallelism int) *Batc
 	}
 }
 
-// Train trains n
-func (t *BatchTrainer) Train(n *deep.Neural, examples, validation Examples, iterations int) {
+// SetAdversarial enables adversarial training: perturbed copies of the given
+// fraction of each batch, generated by attack, are added to the batch
+func (t *BatchTrainer) SetAdversarial(attack Attack, fraction float64) {
+	t.attack, t.adversarial = attack, fraction
+}
+
+type work struct {
+	e           Example
+	adversarial bool
+	// rand seeds random attacks in checkpointed runs
+	rand *rand.Rand
+}
+
+// Train trains n. NaN or infinite values are handled according to the
+// health policy, which by default stops training and returns an error.
+func (t *BatchTrainer) Train(n *deep.Neural, examples, validation Examples, iterations int) error {
+	return t.TrainContext(context.Background(), n, examples, validation, iterations)
+}
+
+// TrainContext trains n like Train, checking ctx before each batch.
+// If ctx is done, it stops and returns an *Interrupted.
+func (t *BatchTrainer) TrainContext(ctx context.Context, n *deep.Neural, examples, validation Examples, iterations int) error {
+	return t.train(ctx, n, nil, examples, validation, iterations)
+}
+
+// Resume continues the run of checkpoint until the given total number of epochs.
+// n must have the checkpointed layout, e.g. from deep.FromDumpE(checkpoint.Network),
+// and the examples must be those of the checkpointed run.
+func (t *BatchTrainer) Resume(ctx context.Context, n *deep.Neural, checkpoint *Checkpoint, examples, validation Examples, iterations int) error {
+	return t.train(ctx, n, checkpoint, examples, validation, iterations)
+}
+
+func (t *BatchTrainer) train(ctx context.Context, n *deep.Neural, checkpoint *Checkpoint, examples, validation Examples, iterations int) error {
 	t.internalb = newBatchTraining(n.Layers, t.parallelism)
+	t.health = Health{}
+	t.diagnostics.init(n)
 
 	train := make(Examples, len(examples))
 	copy(train, examples)
 
-	workCh := make(chan Example, t.parallelism)
+	workCh := make(chan work, t.parallelism)
+	defer close(workCh)
 	nets := make([]*deep.Neural, t.parallelism)
 
 	wg := sync.WaitGroup{}
 	for i := 0; i < t.parallelism; i++ {
 		nets[i] = deep.NewNeural(n.Config)
 
-		go func(id int, workCh <-chan Example) {
+		go func(id int, workCh <-chan work) {
 			n := nets[id]
-			for e := range workCh {
-				n.Forward(e.Input)
-				t.calculateDeltas(n, e.Response, id)
+			for w := range workCh {
+				e, err := w.e, error(nil)
+				if w.adversarial {
+					e, err = perturb(n, t.attack, e, w.rand)
+				}
+				if err != nil {
+					t.errs[id] = cmp.Or(t.errs[id], err)
+				} else if err := n.Forward(e.Input); err != nil {
+					t.errs[id] = cmp.Or(t.errs[id], err)
+				} else {
+					if t.hooked() {
+						t.losses[id] += exampleLoss(n, e)
+					}
+					t.calculateDeltas(n, e, id)
+					t.errs[id] = cmp.Or(t.errs[id], checkDeltas(t.deltas[id]))
+				}
 				wg.Done()
 			}
 		}(i, workCh)
 	}
 
+	t.printer.rate = learningRate(t.solver)
 	t.printer.Init(n)
 	t.solver.Init(n.NumWeights())
+	start, err := t.restore(n, t.solver, checkpoint)
+	if err != nil {
+		return err
+	}
+	t.trainBegin(n, t.solver)
+	defer t.trainEnd(n)
+	if err := resumeSchedule(t.solver, checkpoint); err != nil {
+		return err
+	}
 
 	ts := time.Now()
-	for it := 1; it <= iterations; it++ {
-		train.Shuffle()
+	for it := start; it <= iterations; it++ {
+		t.epochBegin(n, it)
+		t.shuffle(examples, train, it)
 		batches := train.SplitSize(t.batchSize)
+		var seeds *rand.Rand
+		if _, ok := t.attack.(RandomAttack); ok {
+			seeds = t.attackSource(it)
+		}
 
-		for _, b := range batches {
+		for j, b := range batches {
+			if err := interrupted(ctx, it, j); err != nil {
+				return err
+			}
 			currentWeights := n.Weights()
 			for _, n := range nets {
 				n.ApplyWeights(currentWeights)
 			}
 
-			wg.Add(len(b))
+			adversarial := 0
+			if t.attack != nil {
+				adversarial = min(int(math.Round(t.adversarial*float64(len(b)))), len(b))
+			}
+			wg.Add(len(b) + adversarial)
 			for _, item := range b {
-				workCh <- item
+				workCh <- work{e: item}
+			}
+			for _, item := range b[:adversarial] {
+				w := work{e: item, adversarial: true}
+				if seeds != nil {
+					w.rand = rand.New(rand.NewSource(seeds.Int63()))
+				}
+				workCh <- w
 			}
 			wg.Wait()
 
@@ -117,27 +213,60 @@ func (t *BatchTrainer) Train(n *deep.Neural, examples, validation Examples, iter
 				}
 			}
 
-			t.update(n, it)
+			var loss float64
+			for w := range t.losses {
+				loss += t.losses[w]
+				t.losses[w] = 0
+			}
+			if err := t.step(n, it); err != nil {
+				if stop, err := t.check(err, it); stop {
+					return err
+				}
+				continue
+			}
+			if t.batchEnd(n, it, j, loss, len(b)+adversarial) {
+				return nil
+			}
 		}
 
+		t.diagnostics.endEpoch(n, it)
 		if t.verbosity > 0 && it%t.verbosity == 0 && len(validation) > 0 {
 			t.printer.PrintProgress(n, validation, time.Since(ts), it)
 		}
+		stop := t.epochEnd(n, it, validation)
+		if err := t.checkpoint(n, t.solver, it); err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
+	}
+	return nil
+}
+
+// step applies the accumulated gradients of a batch, unless a worker failed
+func (t *BatchTrainer) step(n *deep.Neural, it int) error {
+	var err error
+	for w := range t.errs {
+		err = cmp.Or(err, t.errs[w])
+		t.errs[w] = nil
+	}
+	if err == nil {
+		return t.update(n, it)
+	}
+	for _, iAD := range t.accumulatedDeltas {
+		for _, jAD := range iAD {
+			clear(jAD)
+		}
 	}
+	return err
 }
 
-func (t *BatchTrainer) calculateDeltas(n *deep.Neural, ideal []float64, wid int) {
-	loss := deep.GetLoss(n.Config.Loss)
+func (t *BatchTrainer) calculateDeltas(n *deep.Neural, e Example, wid int) {
 	deltas := t.deltas[wid]
 	partialDeltas := t.partialDeltas[wid]
-	lastDeltas := deltas[len(n.Layers)-1]
 
-	for i, n := range n.Layers[len(n.Layers)-1].Neurons {
-		lastDeltas[i] = loss.Df(
-			n.Value,
-			ideal[i],
-			n.DActivate(n.Value))
-	}
+	outputDeltas(n, e.Response, weight(n, e), deltas[len(n.Layers)-1])
 
 	for i := len(n.Layers) - 2; i >= 0; i-- {
 		l := n.Layers[i]
@@ -165,21 +294,24 @@ func (t *BatchTrainer) calculateDeltas(n *deep.Neural, ideal []float64, wid int)
 	}
 }
 
-func (t *BatchTrainer) update(n *deep.Neural, it int) {
-	var idx int
-	for i, l := range n.Layers {
-		iAD := t.accumulatedDeltas[i]
-		for j, n := range l.Neurons {
-			jAD := iAD[j]
-			for k, s := range n.In {
-				update := t.solver.Update(s.Weight,
-					jAD[k],
-					it,
-					idx)
-				s.Weight += update
-				jAD[k] = 0
-				idx++
-			}
+func (t *BatchTrainer) gradients(n *deep.Neural, examples Examples) [][][]float64 {
+	for _, e := range examples {
+		n.Forward(e.Input)
+		t.calculateDeltas(n, e, 0)
+	}
+	grads := t.partialDeltas[0]
+	t.partialDeltas[0] = newBatchTraining(n.Layers, 1).partialDeltas[0]
+	return grads
+}
+
+func (t *BatchTrainer) update(n *deep.Neural, it int) error {
+	err := t.apply(n, t.solver, it, t.diagnostics, t.policy == HealthSkip, func(i, j, k int) float64 {
+		return t.accumulatedDeltas[i][j][k]
+	})
+	for _, iAD := range t.accumulatedDeltas {
+		for _, jAD := range iAD {
+			clear(jAD)
 		}
 	}
+	return err
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	deep "github.com/patrikeh/go-deep"
+	"github.com/stretchr/testify/assert"
 )
 
 func Benchmark_xor(b *testing.B) {
@@ -19,10 +20,10 @@ func Benchmark_xor(b *testing.B) {
 		Bias:       true,
 	})
 	exs := Examples{
-		{[]float64{0, 0}, []float64{0}},
-		{[]float64{1, 0}, []float64{1}},
-		{[]float64{0, 1}, []float64{1}},
-		{[]float64{1, 1}, []float64{0}},
+		{Input: []float64{0, 0}, Response: []float64{0}},
+		{Input: []float64{1, 0}, Response: []float64{1}},
+		{Input: []float64{0, 1}, Response: []float64{1}},
+		{Input: []float64{1, 1}, Response: []float64{0}},
 	}
 	const minExamples = 4000
 	var dupExs Examples
@@ -37,3 +38,56 @@ func Benchmark_xor(b *testing.B) {
 		trainer.Train(n, dupExs, dupExs, iterations)
 	}
 }
+
+func Test_PrunedFineTune(t *testing.T) {
+	rand.Seed(0)
+	n := deep.NewNeural(&deep.Config{
+		Inputs:     2,
+		Layout:     []int{8, 1},
+		Activation: deep.ActivationTanh,
+		Mode:       deep.ModeBinary,
+		Weight:     deep.NewNormal(1, 0),
+		Bias:       true,
+	})
+	n.Prune(0.5, deep.PruneGlobal)
+	sparsity := n.Sparsity()
+
+	trainer := NewBatchTrainer(NewAdam(0.01, 0, 0, 0), 0, 2, 2)
+	trainer.Train(n, data, nil, 50)
+
+	assert.Equal(t, sparsity, n.Sparsity())
+	for _, l := range n.Layers {
+		for _, neuron := range l.Neurons {
+			for _, s := range neuron.In {
+				if s.Pruned {
+					assert.Equal(t, 0.0, s.Weight)
+				}
+			}
+		}
+	}
+}
+
+func Test_FrozenTrunk(t *testing.T) {
+	for _, trainer := range []Trainer{
+		NewTrainer(NewSGD(0.1, 0.5, 0, false), 0),
+		NewBatchTrainer(NewAdam(0.01, 0, 0, 0), 0, 2, 2),
+	} {
+		rand.Seed(0)
+		n := deep.NewNeural(&deep.Config{
+			Inputs:     2,
+			Layout:     []int{4, 4, 1},
+			Activation: deep.ActivationTanh,
+			Mode:       deep.ModeBinary,
+			Weight:     deep.NewNormal(1, 0),
+			Bias:       true,
+		})
+		n.FreezeTrunk()
+		before := n.Weights()
+
+		assert.NoError(t, trainer.Train(n, data, nil, 20))
+
+		after := n.Weights()
+		assert.Equal(t, before[:2], after[:2])
+		assert.NotEqual(t, before[2], after[2])
+	}
+}
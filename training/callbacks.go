@@ -0,0 +1,127 @@
+package training
+
+import (
+	"slices"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/patrikeh/go-deep/metrics"
+)
+
+// Progress is the state of training passed to callbacks
+type Progress struct {
+	Epoch int
+	// Batch is the index of the batch in the epoch, of the example for the online
+	// trainer, and -1 in OnEpochEnd
+	Batch int
+	// Loss is the mean weighted training loss of the batch, or of the epoch in OnEpochEnd
+	Loss float64
+	// Validation holds the predictions on the validation examples in OnEpochEnd,
+	// nil without validation examples
+	Validation *metrics.Results
+	// ValidationLoss is the validation loss in OnEpochEnd, as printed by StatsPrinter
+	ValidationLoss float64
+}
+
+// Callback hooks into training. OnBatchEnd and OnEpochEnd stop training by
+// returning true, in which case Train returns nil. OnTrainEnd is called however
+// training ends, with the progress of the last completed epoch.
+type Callback interface {
+	OnTrainBegin(n *deep.Neural)
+	OnEpochBegin(n *deep.Neural, epoch int)
+	OnBatchEnd(n *deep.Neural, p Progress) bool
+	OnEpochEnd(n *deep.Neural, p Progress) bool
+	OnTrainEnd(n *deep.Neural, p Progress)
+}
+
+// NopCallback does nothing, embed it to implement only some hooks of Callback
+type NopCallback struct{}
+
+// OnTrainBegin does nothing
+func (NopCallback) OnTrainBegin(n *deep.Neural) {}
+
+// OnEpochBegin does nothing
+func (NopCallback) OnEpochBegin(n *deep.Neural, epoch int) {}
+
+// OnBatchEnd does nothing
+func (NopCallback) OnBatchEnd(n *deep.Neural, p Progress) bool { return false }
+
+// OnEpochEnd does nothing
+func (NopCallback) OnEpochEnd(n *deep.Neural, p Progress) bool { return false }
+
+// OnTrainEnd does nothing
+func (NopCallback) OnTrainEnd(n *deep.Neural, p Progress) {}
+
+type hooks struct {
+	callbacks []Callback
+	// active are the callbacks of the current run, including the solver's schedule if it is a Callback
+	active   []Callback
+	progress Progress
+	loss     float64
+	examples int
+}
+
+// SetCallbacks sets the callbacks called during training, in order
+func (h *hooks) SetCallbacks(callbacks ...Callback) {
+	h.callbacks = callbacks
+}
+
+// hooked reports whether callbacks are active, and training losses need to be computed
+func (h *hooks) hooked() bool {
+	return len(h.active) > 0
+}
+
+func (h *hooks) trainBegin(n *deep.Neural, solver Solver) {
+	h.active = slices.Clone(h.callbacks)
+	if c := scheduleCallback(solver); c != nil && !slices.Contains(h.callbacks, c) {
+		h.active = append(h.active, c)
+	}
+	h.progress = Progress{}
+	for _, c := range h.active {
+		c.OnTrainBegin(n)
+	}
+}
+
+func (h *hooks) epochBegin(n *deep.Neural, epoch int) {
+	h.loss, h.examples = 0, 0
+	for _, c := range h.active {
+		c.OnEpochBegin(n, epoch)
+	}
+}
+
+// batchEnd passes the summed loss of a batch of the given size, returning whether to stop
+func (h *hooks) batchEnd(n *deep.Neural, epoch, batch int, loss float64, size int) bool {
+	if !h.hooked() {
+		return false
+	}
+	h.loss, h.examples = h.loss+loss, h.examples+size
+	p := Progress{Epoch: epoch, Batch: batch, Loss: loss / float64(size)}
+	stop := false
+	for _, c := range h.active {
+		stop = c.OnBatchEnd(n, p) || stop
+	}
+	return stop
+}
+
+// epochEnd evaluates n on validation, returning whether to stop
+func (h *hooks) epochEnd(n *deep.Neural, epoch int, validation Examples) bool {
+	if !h.hooked() {
+		return false
+	}
+	p := Progress{Epoch: epoch, Batch: -1, Loss: h.loss / float64(max(h.examples, 1))}
+	if len(validation) > 0 {
+		results := metrics.Evaluate(n, validation)
+		p.Validation, p.ValidationLoss = &results, crossValidate(n, validation)
+	}
+	h.progress = p
+	stop := false
+	for _, c := range h.active {
+		stop = c.OnEpochEnd(n, p) || stop
+	}
+	return stop
+}
+
+func (h *hooks) trainEnd(n *deep.Neural) {
+	for _, c := range h.active {
+		c.OnTrainEnd(n, h.progress)
+	}
+}
@@ -0,0 +1,89 @@
+package training
+
+import (
+	"math/rand"
+	"testing"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	NopCallback
+	calls    []string
+	batches  []Progress
+	epochs   []Progress
+	stopAt   int
+	finished Progress
+}
+
+func (r *recorder) OnTrainBegin(n *deep.Neural) {
+	r.calls = append(r.calls, "train")
+}
+
+func (r *recorder) OnEpochBegin(n *deep.Neural, epoch int) {
+	r.calls = append(r.calls, "epoch")
+}
+
+func (r *recorder) OnBatchEnd(n *deep.Neural, p Progress) bool {
+	r.batches = append(r.batches, p)
+	return false
+}
+
+func (r *recorder) OnEpochEnd(n *deep.Neural, p Progress) bool {
+	r.epochs = append(r.epochs, p)
+	return p.Epoch == r.stopAt
+}
+
+func (r *recorder) OnTrainEnd(n *deep.Neural, p Progress) {
+	r.calls = append(r.calls, "end")
+	r.finished = p
+}
+
+func Test_Callbacks(t *testing.T) {
+	examples := Examples{
+		{Input: []float64{0, 0}, Response: []float64{0}},
+		{Input: []float64{0, 1}, Response: []float64{1}},
+		{Input: []float64{1, 0}, Response: []float64{1}},
+		{Input: []float64{1, 1}, Response: []float64{0}},
+	}
+	type hookedTrainer interface {
+		Trainer
+		SetCallbacks(...Callback)
+	}
+	for name, tc := range map[string]struct {
+		trainer hookedTrainer
+		batches int
+	}{
+		"online": {NewTrainer(NewSGD(0.5, 0, 0, false), 0), 4},
+		"batch":  {NewBatchTrainer(NewSGD(0.5, 0, 0, false), 0, 2, 2), 2},
+	} {
+		rand.Seed(0)
+		r, other := &recorder{stopAt: 3}, &recorder{}
+		tc.trainer.SetCallbacks(r, other)
+		assert.NoError(t, tc.trainer.Train(healthNetwork(), examples, examples, 10), name)
+
+		assert.Equal(t, []string{"train", "epoch", "epoch", "epoch", "end"}, r.calls, name)
+		assert.Len(t, r.batches, 3*tc.batches, name)
+		assert.Len(t, other.epochs, 3, name)
+		assert.Equal(t, r.epochs[2], r.finished, name)
+
+		for i, p := range r.epochs {
+			assert.Equal(t, i+1, p.Epoch, name)
+			assert.Equal(t, -1, p.Batch, name)
+			assert.Len(t, p.Validation.Predicted, len(examples), name)
+			assert.Greater(t, p.ValidationLoss, 0.0, name)
+
+			var sum float64
+			for _, b := range r.batches[i*tc.batches : (i+1)*tc.batches] {
+				assert.Equal(t, i+1, b.Epoch, name)
+				sum += b.Loss
+			}
+			assert.InDelta(t, sum/float64(tc.batches), p.Loss, 1e-9, name)
+		}
+
+		tc.trainer.SetCallbacks()
+		assert.NoError(t, tc.trainer.Train(healthNetwork(), examples, examples, 1), name)
+		assert.Len(t, r.epochs, 3, name)
+	}
+}
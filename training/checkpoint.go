@@ -0,0 +1,177 @@
+package training
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	deep "github.com/patrikeh/go-deep"
+)
+
+// Stateful is implemented by solvers whose internal state, such as momentum,
+// can be saved in checkpoints. Solvers are initialized before SetState.
+type Stateful interface {
+	State() map[string][]float64
+	SetState(state map[string][]float64) error
+}
+
+// Checkpoint is the state of a training run after an epoch, from which it can be resumed
+type Checkpoint struct {
+	Network *deep.Dump
+	// Solver is the solver's state, if it is Stateful
+	Solver map[string][]float64 `json:",omitempty"`
+	// Schedule is the state of the solver's schedule, if it is Stateful
+	Schedule map[string][]float64 `json:",omitempty"`
+	// Epoch is the number of completed epochs
+	Epoch int
+	// Seed determines the order of examples in each epoch
+	Seed int64
+}
+
+// SaveCheckpoint returns a function writing checkpoints to path, for use with SetCheckpoints.
+// The previous checkpoint is only replaced once the new one is completely written.
+func SaveCheckpoint(path string) func(*Checkpoint) error {
+	return func(c *Checkpoint) error {
+		bytes, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		if _, err := tmp.Write(bytes); err != nil {
+			tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		return os.Rename(tmp.Name(), path)
+	}
+}
+
+// LoadCheckpoint reads a checkpoint written by SaveCheckpoint
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Checkpoint
+	if err := json.Unmarshal(bytes, &c); err != nil {
+		return nil, err
+	}
+	if c.Network == nil {
+		return nil, fmt.Errorf("Invalid checkpoint - missing network")
+	}
+	return &c, nil
+}
+
+type checkpointing struct {
+	every  int
+	save   func(*Checkpoint) error
+	seed   int64
+	seeded bool
+}
+
+// SetCheckpoints saves a checkpoint every given number of epochs, 0 disables checkpoints.
+// Checkpointed runs shuffle examples reproducibly, so that they can be resumed exactly.
+func (c *checkpointing) SetCheckpoints(every int, save func(*Checkpoint) error) {
+	c.every, c.save = every, save
+}
+
+// restore applies checkpoint to n and solver, returning the first epoch to train.
+// A nil checkpoint starts a new run.
+func (c *checkpointing) restore(n *deep.Neural, solver Solver, checkpoint *Checkpoint) (int, error) {
+	if checkpoint == nil {
+		c.seeded = c.every > 0
+		if c.seeded {
+			c.seed = rand.Int63()
+		}
+		return 1, nil
+	}
+
+	restored, err := deep.FromDumpE(checkpoint.Network)
+	if err != nil {
+		return 0, err
+	}
+	if err := n.ApplyWeightsE(restored.Weights()); err != nil {
+		return 0, err
+	}
+	if checkpoint.Solver != nil {
+		s, ok := solver.(Stateful)
+		if !ok {
+			return 0, fmt.Errorf("Invalid checkpoint - solver %T has no state", solver)
+		}
+		if err := s.SetState(checkpoint.Solver); err != nil {
+			return 0, err
+		}
+	}
+	c.seed, c.seeded = checkpoint.Seed, true
+	return checkpoint.Epoch + 1, nil
+}
+
+// shuffle orders the examples of an epoch into order. Checkpointed runs
+// shuffle the original order with an RNG derived from the seed and epoch.
+func (c *checkpointing) shuffle(examples, order Examples, epoch int) {
+	if !c.seeded {
+		order.Shuffle()
+		return
+	}
+	copy(order, examples)
+	r := rand.New(rand.NewSource(c.seed + int64(epoch)))
+	for i := range order {
+		j := r.Intn(i + 1)
+		order[i], order[j] = order[j], order[i]
+	}
+}
+
+// attackSource returns a source of seeds for the random attacks of an epoch in
+// checkpointed runs, derived from the seed and epoch like the order of examples.
+// It is nil for other runs.
+func (c *checkpointing) attackSource(epoch int) *rand.Rand {
+	if !c.seeded {
+		return nil
+	}
+	return rand.New(rand.NewSource(c.seed - int64(epoch)))
+}
+
+// checkpoint saves a checkpoint if one is due after epoch
+func (c *checkpointing) checkpoint(n *deep.Neural, solver Solver, epoch int) error {
+	if c.every <= 0 || epoch%c.every != 0 {
+		return nil
+	}
+	checkpoint := &Checkpoint{Network: n.Dump(), Epoch: epoch, Seed: c.seed}
+	if s, ok := solver.(Stateful); ok {
+		checkpoint.Solver = s.State()
+	}
+	if s, ok := schedule(solver).(Stateful); ok {
+		checkpoint.Schedule = s.State()
+	}
+	return c.save(checkpoint)
+}
+
+// resumeSchedule restores the state of the solver's schedule from checkpoint.
+// It follows the callbacks' OnTrainBegin, which resets stateful schedules.
+func resumeSchedule(solver Solver, checkpoint *Checkpoint) error {
+	if checkpoint == nil || checkpoint.Schedule == nil {
+		return nil
+	}
+	s, ok := schedule(solver).(Stateful)
+	if !ok {
+		return fmt.Errorf("Invalid checkpoint - schedule %T has no state", schedule(solver))
+	}
+	return s.SetState(checkpoint.Schedule)
+}
+
+// state checks the size of a saved solver vector
+func state(saved map[string][]float64, name string, size int) ([]float64, error) {
+	v, ok := saved[name]
+	if !ok || len(v) != size {
+		return nil, fmt.Errorf("Invalid solver state - %s expected: %d got: %d", name, size, len(v))
+	}
+	return append([]float64(nil), v...), nil
+}
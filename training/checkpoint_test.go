@@ -0,0 +1,144 @@
+package training
+
+import (
+	"context"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/stretchr/testify/assert"
+)
+
+type resumable interface {
+	TrainContext(ctx context.Context, n *deep.Neural, examples, validation Examples, iterations int) error
+	Resume(ctx context.Context, n *deep.Neural, checkpoint *Checkpoint, examples, validation Examples, iterations int) error
+	SetCheckpoints(every int, save func(*Checkpoint) error)
+}
+
+func Test_Resume(t *testing.T) {
+	examples := Examples{
+		{Input: []float64{0, 0}, Response: []float64{0}},
+		{Input: []float64{0, 1}, Response: []float64{1}},
+		{Input: []float64{1, 0}, Response: []float64{1}},
+		{Input: []float64{1, 1}, Response: []float64{0}},
+		{Input: []float64{0.5, 0.5}, Response: []float64{0}},
+	}
+	for name, trainer := range map[string]func() resumable{
+		"online": func() resumable { return NewTrainer(NewAdam(0.05, 0, 0, 0), 0) },
+		"batch":  func() resumable { return NewBatchTrainer(NewSGD(0.5, 0.9, 0, false), 0, 2, 1) },
+	} {
+		rand.Seed(0)
+		n := healthNetwork()
+		var checkpoints []*Checkpoint
+		full := trainer()
+		full.SetCheckpoints(3, func(c *Checkpoint) error {
+			checkpoints = append(checkpoints, c)
+			return nil
+		})
+		assert.NoError(t, full.TrainContext(context.Background(), n, examples, nil, 8), name)
+		assert.Len(t, checkpoints, 2, name)
+		assert.Equal(t, 3, checkpoints[0].Epoch, name)
+		assert.NotEmpty(t, checkpoints[0].Solver, name)
+
+		resumed, err := deep.FromDumpE(checkpoints[0].Network)
+		assert.NoError(t, err, name)
+		assert.NoError(t, trainer().Resume(context.Background(), resumed, checkpoints[0], examples, nil, 8), name)
+		assert.Equal(t, n.Weights(), resumed.Weights(), name)
+	}
+}
+
+func Test_SaveCheckpoint(t *testing.T) {
+	rand.Seed(0)
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	n := healthNetwork()
+	trainer := NewBatchTrainer(NewAdam(0.01, 0, 0, 0), 0, 2, 2)
+	trainer.SetCheckpoints(2, SaveCheckpoint(path))
+	assert.NoError(t, trainer.Train(n, healthExamples()[:2], nil, 4))
+
+	checkpoint, err := LoadCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, checkpoint.Epoch)
+	assert.Len(t, checkpoint.Solver["m"], n.NumWeights())
+	restored, err := deep.FromDumpE(checkpoint.Network)
+	assert.NoError(t, err)
+	assert.Equal(t, n.Weights(), restored.Weights())
+
+	_, err = LoadCheckpoint(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func Test_SolverState(t *testing.T) {
+	adam := NewAdam(0, 0, 0, 0)
+	adam.Init(3)
+	adam.Update(0, 1, 1, 0)
+	saved := adam.State()
+
+	restored := NewAdam(0, 0, 0, 0)
+	restored.Init(3)
+	assert.NoError(t, restored.SetState(saved))
+	assert.Equal(t, adam.Update(0, 0.5, 2, 0), restored.Update(0, 0.5, 2, 0))
+
+	restored.Init(2)
+	assert.Error(t, restored.SetState(saved))
+
+	sgd := NewSGD(0, 0.9, 0, false)
+	sgd.Init(3)
+	assert.Error(t, sgd.SetState(saved))
+}
+
+func Test_ResumeSchedule(t *testing.T) {
+	examples := healthExamples()[:2]
+	run := func() (*ReduceOnPlateau, *SGD, *BatchTrainer) {
+		plateau := NewReduceOnPlateau(0.5, 1, 0, 0)
+		solver := NewSGD(1e-300, 0, 0, false)
+		solver.SetSchedule(plateau)
+		return plateau, solver, NewBatchTrainer(solver, 0, 1, 1)
+	}
+
+	rand.Seed(0)
+	plateau, solver, full := run()
+	var checkpoints []*Checkpoint
+	full.SetCheckpoints(3, func(c *Checkpoint) error {
+		checkpoints = append(checkpoints, c)
+		return nil
+	})
+	n := healthNetwork()
+	assert.NoError(t, full.Train(n, examples, examples, 6))
+	assert.Equal(t, []float64{2}, checkpoints[0].Schedule["reductions"])
+
+	resumedPlateau, resumedSolver, resumed := run()
+	restored, err := deep.FromDumpE(checkpoints[0].Network)
+	assert.NoError(t, err)
+	assert.NoError(t, resumed.Resume(context.Background(), restored, checkpoints[0], examples, examples, 6))
+	assert.Equal(t, plateau.reductions, resumedPlateau.reductions)
+	assert.Equal(t, solver.LearningRate(7), resumedSolver.LearningRate(7))
+
+	checkpoints[0].Schedule = map[string][]float64{"wait": {0}}
+	_, _, resumed = run()
+	assert.Error(t, resumed.Resume(context.Background(), restored, checkpoints[0], examples, examples, 6))
+}
+
+func Test_ResumeAdversarial(t *testing.T) {
+	examples := healthExamples()[:2]
+	trainer := func() *BatchTrainer {
+		trainer := NewBatchTrainer(NewAdam(0.05, 0, 0, 0), 0, 2, 1)
+		trainer.SetAdversarial(PGD{Epsilon: 0.3, Alpha: 0.01, Steps: 2, RandomStart: true}, 1)
+		return trainer
+	}
+
+	rand.Seed(0)
+	n := healthNetwork()
+	var checkpoints []*Checkpoint
+	full := trainer()
+	full.SetCheckpoints(2, func(c *Checkpoint) error {
+		checkpoints = append(checkpoints, c)
+		return nil
+	})
+	assert.NoError(t, full.Train(n, examples, nil, 5))
+
+	resumed, err := deep.FromDumpE(checkpoints[0].Network)
+	assert.NoError(t, err)
+	assert.NoError(t, trainer().Resume(context.Background(), resumed, checkpoints[0], examples, nil, 5))
+	assert.Equal(t, n.Weights(), resumed.Weights())
+}
@@ -0,0 +1,36 @@
+package training
+
+import (
+	"context"
+	"fmt"
+)
+
+// Interrupted is returned when training is cancelled through its context.
+// Weights are only updated by complete batches, so the network is left as
+// trained by the batches before the cancellation.
+type Interrupted struct {
+	// Epoch during which training was cancelled
+	Epoch int
+	// Batches completed in Epoch, examples for the online trainer
+	Batches int
+	// Err is the context's error
+	Err error
+}
+
+func (e *Interrupted) Error() string {
+	return fmt.Sprintf("training interrupted in epoch %d after %d batches: %v", e.Epoch, e.Batches, e.Err)
+}
+
+func (e *Interrupted) Unwrap() error {
+	return e.Err
+}
+
+// interrupted returns an *Interrupted if ctx is done
+func interrupted(ctx context.Context, epoch, batches int) error {
+	select {
+	case <-ctx.Done():
+		return &Interrupted{Epoch: epoch, Batches: batches, Err: ctx.Err()}
+	default:
+		return nil
+	}
+}
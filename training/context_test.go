@@ -0,0 +1,71 @@
+package training
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countdown is a context that is cancelled after it has been checked checks times
+type countdown struct {
+	context.Context
+	checks int
+	done   chan struct{}
+}
+
+func newCountdown(checks int) *countdown {
+	return &countdown{Context: context.Background(), checks: checks, done: make(chan struct{})}
+}
+
+func (c *countdown) Done() <-chan struct{} {
+	if c.checks == 0 {
+		close(c.done)
+	}
+	c.checks--
+	return c.done
+}
+
+func (c *countdown) Err() error {
+	if c.checks < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func Test_TrainContext(t *testing.T) {
+	examples := Examples{
+		{Input: []float64{0, 0}, Response: []float64{0}},
+		{Input: []float64{0, 1}, Response: []float64{1}},
+		{Input: []float64{1, 0}, Response: []float64{1}},
+		{Input: []float64{1, 1}, Response: []float64{0}},
+	}
+	for name, trainer := range map[string]Trainer{
+		"online": NewTrainer(NewSGD(0.1, 0, 0, false), 0),
+		"batch":  NewBatchTrainer(NewSGD(0.1, 0, 0, false), 0, 2, 2),
+	} {
+		rand.Seed(0)
+		n := healthNetwork()
+		before := n.Weights()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := trainer.TrainContext(ctx, n, examples, nil, 10)
+		assert.True(t, errors.Is(err, context.Canceled), name)
+		assert.Equal(t, before, n.Weights(), name)
+
+		// online trains on 4 examples per epoch, batch on 2 batches
+		err = trainer.TrainContext(newCountdown(5), n, examples, nil, 10)
+		var interrupted *Interrupted
+		assert.True(t, errors.As(err, &interrupted), name)
+		assert.Equal(t, map[string]int{"online": 2, "batch": 3}[name], interrupted.Epoch, name)
+		assert.Equal(t, map[string]int{"online": 1, "batch": 1}[name], interrupted.Batches, name)
+		assert.NotEqual(t, before, n.Weights(), name)
+		_, err = n.PredictE(examples[0].Input)
+		assert.NoError(t, err, name)
+
+		assert.NoError(t, trainer.TrainContext(context.Background(), n, examples, nil, 2), name)
+	}
+}
@@ -0,0 +1,45 @@
+package training
+
+import deep "github.com/patrikeh/go-deep"
+
+// outputDeltas computes the derivative of the loss, scaled by weight, with
+// respect to the input sum of each output neuron. Each head contributes
+// the derivative of its own loss, scaled by the head weight.
+func outputDeltas(n *deep.Neural, ideal []float64, weight float64, deltas []float64) {
+	out := n.Layers[len(n.Layers)-1].Neurons
+	ideal = n.Config.TargetScaler.Transform(ideal)
+	var start int
+	for _, h := range n.Config.OutputHeads() {
+		end := start + h.Outputs
+		headDeltas(n.Config.HeadLoss(h), n.Config.HeadActivation(h), out[start:end], ideal[start:end], deltas[start:end])
+		for i := start; i < end; i++ {
+			deltas[i] *= weight * h.Weight
+		}
+		start = end
+	}
+}
+
+func headDeltas(loss deep.Loss, act deep.ActivationType, out []*deep.Neuron, ideal, deltas []float64) {
+	if d, ok := loss.(deep.Deltas); ok {
+		estimate, activation := make([]float64, len(out)), make([]float64, len(out))
+		for i, neuron := range out {
+			estimate[i], activation[i] = neuron.Value, neuron.DActivate(neuron.Value)
+		}
+		copy(deltas, d.Deltas(estimate, ideal, activation))
+	} else {
+		for i, neuron := range out {
+			deltas[i] = loss.Df(neuron.Value, ideal[i], neuron.DActivate(neuron.Value))
+		}
+	}
+
+	if c, ok := loss.(deep.Canonical); act == deep.ActivationSoftmax && !(ok && c.Canonical(act)) {
+		// Df is with respect to the softmax outputs, apply the softmax Jacobian
+		var dot float64
+		for i, neuron := range out {
+			dot += deltas[i] * neuron.Value
+		}
+		for i, neuron := range out {
+			deltas[i] = neuron.Value * (deltas[i] - dot)
+		}
+	}
+}
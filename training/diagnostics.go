@@ -0,0 +1,118 @@
+package training
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"text/tabwriter"
+
+	deep "github.com/patrikeh/go-deep"
+)
+
+// Diagnostics records per-layer gradient norms during training and
+// periodically reports weight, activation and gradient statistics
+type Diagnostics struct {
+	// Every is the number of epochs between reports, 0 disables reporting
+	Every int
+	// Sample holds the inputs for activation statistics
+	Sample Examples
+	// Bins is the number of histogram bins, 10 by default
+	Bins int
+	// Out receives reports, os.Stdout by default
+	Out io.Writer
+	// GradientNorms holds the mean L2 norm of each layer's gradient per epoch
+	GradientNorms [][]float64
+
+	step, epoch []float64
+	steps       int
+}
+
+type diagnosing struct {
+	diagnostics *Diagnostics
+}
+
+// SetDiagnostics enables collection of training diagnostics, nil disables it
+func (d *diagnosing) SetDiagnostics(diagnostics *Diagnostics) {
+	d.diagnostics = diagnostics
+}
+
+func (d *Diagnostics) init(n *deep.Neural) {
+	if d == nil {
+		return
+	}
+	d.GradientNorms = nil
+	d.step, d.epoch, d.steps = make([]float64, len(n.Layers)), make([]float64, len(n.Layers)), 0
+}
+
+// observe records a gradient of a weight in layer i
+func (d *Diagnostics) observe(i int, gradient float64) {
+	if d == nil {
+		return
+	}
+	d.step[i] += gradient * gradient
+}
+
+// endStep closes a weight update
+func (d *Diagnostics) endStep() {
+	if d == nil {
+		return
+	}
+	for i, sq := range d.step {
+		d.epoch[i] += math.Sqrt(sq)
+		d.step[i] = 0
+	}
+	d.steps++
+}
+
+// endEpoch records the epoch's gradient norms and reports if due
+func (d *Diagnostics) endEpoch(n *deep.Neural, epoch int) {
+	if d == nil {
+		return
+	}
+	norms := make([]float64, len(d.epoch))
+	for i := range d.epoch {
+		if d.steps > 0 {
+			norms[i] = d.epoch[i] / float64(d.steps)
+		}
+		d.epoch[i] = 0
+	}
+	d.steps = 0
+	d.GradientNorms = append(d.GradientNorms, norms)
+
+	if d.Every > 0 && epoch%d.Every == 0 {
+		d.Report(n, epoch)
+	}
+}
+
+// Report writes per-layer weight, activation and gradient statistics
+func (d *Diagnostics) Report(n *deep.Neural, epoch int) {
+	bins := iparam(d.Bins, 10)
+	inputs := make([][]float64, len(d.Sample))
+	for i, e := range d.Sample {
+		inputs[i] = e.Input
+	}
+	weights := n.WeightStats(bins)
+	activations, err := n.ActivationStats(inputs, bins)
+
+	out := d.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Epoch %d\n", epoch)
+	fmt.Fprintf(w, "Layer\tWeight mean\tstd\tmin\tmax\thistogram\tDead\tSaturated\tGradient norm\t\n")
+	for i, s := range weights {
+		fmt.Fprintf(w, "%d\t%.4f\t%.4f\t%.4f\t%.4f\t%s\t", i, s.Mean, s.Std, s.Min, s.Max, s.Histogram.Sparkline())
+		if err == nil && len(inputs) > 0 {
+			fmt.Fprintf(w, "%.2f\t%.2f\t", activations[i].Dead, activations[i].Saturated)
+		} else {
+			fmt.Fprintf(w, "-\t-\t")
+		}
+		if len(d.GradientNorms) > 0 {
+			fmt.Fprintf(w, "%.4g\t", d.GradientNorms[len(d.GradientNorms)-1][i])
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+}
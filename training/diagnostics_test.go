@@ -0,0 +1,46 @@
+package training
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Diagnostics(t *testing.T) {
+	for _, trainer := range []interface {
+		Trainer
+		SetDiagnostics(*Diagnostics)
+	}{
+		NewTrainer(NewSGD(0.1, 0, 0, false), 0),
+		NewBatchTrainer(NewSGD(0.1, 0, 0, false), 0, 4, 2),
+	} {
+		rand.Seed(0)
+		n := deep.NewNeural(&deep.Config{
+			Inputs:     2,
+			Layout:     []int{4, 1},
+			Activation: deep.ActivationReLU,
+			Mode:       deep.ModeBinary,
+			Weight:     deep.NewNormal(0.5, 0),
+			Bias:       true,
+		})
+		n.Freeze(1)
+
+		var buf bytes.Buffer
+		diagnostics := &Diagnostics{Every: 2, Sample: data, Out: &buf}
+		trainer.SetDiagnostics(diagnostics)
+		assert.NoError(t, trainer.Train(n, data, nil, 4))
+
+		assert.Len(t, diagnostics.GradientNorms, 4)
+		for _, norms := range diagnostics.GradientNorms {
+			assert.Greater(t, norms[0], 0.)
+			assert.Equal(t, 0., norms[1], "frozen layers have no gradients")
+		}
+		assert.Contains(t, buf.String(), "Epoch 2")
+		assert.Contains(t, buf.String(), "Epoch 4")
+		assert.NotContains(t, buf.String(), "Epoch 3")
+		assert.Contains(t, buf.String(), "Gradient norm")
+	}
+}
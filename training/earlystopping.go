@@ -0,0 +1,116 @@
+package training
+
+import (
+	"math"
+
+	deep "github.com/patrikeh/go-deep"
+)
+
+// Metric is a validation metric monitored during training
+type Metric int
+
+const (
+	// MetricLoss is the validation loss, lower is better
+	MetricLoss Metric = 0
+	// MetricAccuracy is the validation accuracy
+	MetricAccuracy Metric = 1
+	// MetricF1 is the validation macro F1 score
+	MetricF1 Metric = 2
+)
+
+func (m Metric) String() string {
+	switch m {
+	case MetricLoss:
+		return "loss"
+	case MetricAccuracy:
+		return "accuracy"
+	case MetricF1:
+		return "F1"
+	}
+	return "N/A"
+}
+
+// value returns the metric of p
+func (m Metric) value(p Progress) float64 {
+	switch m {
+	case MetricAccuracy:
+		return p.Validation.Accuracy()
+	case MetricF1:
+		return p.Validation.Confusion().MacroF1()
+	}
+	return p.ValidationLoss
+}
+
+// improves reports whether value improves on best by more than delta
+func (m Metric) improves(value, best, delta float64) bool {
+	if m == MetricLoss {
+		return value < best-delta
+	}
+	return value > best+delta
+}
+
+// EarlyStopping is a callback that stops training once the monitored
+// validation metric has not improved by more than MinDelta for Patience
+// epochs, and then restores the weights of the best epoch. It has no
+// effect without validation examples.
+type EarlyStopping struct {
+	NopCallback
+	Metric   Metric
+	Patience int
+	MinDelta float64
+
+	// Best is the best value of the metric in the last training run
+	Best float64
+	// BestEpoch is the epoch of Best, 0 if no epoch was evaluated
+	BestEpoch int
+	// Stopped is the epoch training was stopped in, 0 if it ran to completion
+	Stopped int
+
+	weights [][][]float64
+	wait    int
+}
+
+// NewEarlyStopping returns an EarlyStopping monitoring metric,
+// patience defaults to 5 epochs
+func NewEarlyStopping(metric Metric, patience int, minDelta float64) *EarlyStopping {
+	return &EarlyStopping{
+		Metric:   metric,
+		Patience: iparam(patience, 5),
+		MinDelta: minDelta,
+	}
+}
+
+// OnTrainBegin resets the best epoch
+func (e *EarlyStopping) OnTrainBegin(n *deep.Neural) {
+	e.Best, e.BestEpoch, e.Stopped = math.Inf(1), 0, 0
+	if e.Metric != MetricLoss {
+		e.Best = math.Inf(-1)
+	}
+	e.weights, e.wait = nil, 0
+}
+
+// OnEpochEnd records the weights of improved epochs, and stops once patience runs out
+func (e *EarlyStopping) OnEpochEnd(n *deep.Neural, p Progress) bool {
+	if p.Validation == nil {
+		return false
+	}
+	if value := e.Metric.value(p); e.Metric.improves(value, e.Best, e.MinDelta) {
+		e.Best, e.BestEpoch = value, p.Epoch
+		e.weights, e.wait = n.Weights(), 0
+		return false
+	}
+	e.wait++
+	if e.wait >= iparam(e.Patience, 5) {
+		e.Stopped = p.Epoch
+		return true
+	}
+	return false
+}
+
+// OnTrainEnd restores the weights of the best epoch
+func (e *EarlyStopping) OnTrainEnd(n *deep.Neural, p Progress) {
+	if e.weights != nil {
+		n.ApplyWeights(e.weights)
+	}
+	e.weights = nil
+}
@@ -0,0 +1,86 @@
+package training
+
+import (
+	"math/rand"
+	"testing"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/patrikeh/go-deep/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EarlyStopping(t *testing.T) {
+	rand.Seed(0)
+	n := healthNetwork()
+	e := NewEarlyStopping(MetricLoss, 2, 0.01)
+	validation := &metrics.Results{}
+
+	e.OnTrainBegin(n)
+	var best [][][]float64
+	for epoch, loss := range []float64{0.5, 0.3, 0.295, 0.4, 0.1} {
+		n.ApplyWeights(randomWeights(n))
+		if epoch == 1 {
+			best = n.Weights()
+		}
+		stop := e.OnEpochEnd(n, Progress{Epoch: epoch + 1, Validation: validation, ValidationLoss: loss})
+		assert.Equal(t, epoch == 3, stop, "epoch %d", epoch+1)
+		if stop {
+			break
+		}
+	}
+	e.OnTrainEnd(n, Progress{})
+
+	assert.Equal(t, 0.3, e.Best)
+	assert.Equal(t, 2, e.BestEpoch)
+	assert.Equal(t, 4, e.Stopped)
+	assert.Equal(t, best, n.Weights())
+}
+
+func Test_EarlyStoppingAccuracy(t *testing.T) {
+	rand.Seed(0)
+	n := healthNetwork()
+	e := NewEarlyStopping(MetricAccuracy, 1, 0)
+	right := &metrics.Results{Predicted: [][]float64{{0.9}}, Actual: [][]float64{{1}}}
+	wrong := &metrics.Results{Predicted: [][]float64{{0.1}}, Actual: [][]float64{{1}}}
+
+	e.OnTrainBegin(n)
+	assert.False(t, e.OnEpochEnd(n, Progress{Epoch: 1, Validation: wrong}))
+	assert.False(t, e.OnEpochEnd(n, Progress{Epoch: 2, Validation: right}))
+	assert.True(t, e.OnEpochEnd(n, Progress{Epoch: 3, Validation: right}))
+	assert.Equal(t, 1.0, e.Best)
+	assert.Equal(t, 2, e.BestEpoch)
+
+	// no validation examples, no effect
+	e.OnTrainBegin(n)
+	assert.False(t, e.OnEpochEnd(n, Progress{Epoch: 1}))
+	assert.Equal(t, 0, e.BestEpoch)
+}
+
+func Test_EarlyStoppingTraining(t *testing.T) {
+	rand.Seed(0)
+	n := healthNetwork()
+	examples := Examples{
+		{Input: []float64{0, 1}, Response: []float64{1}},
+		{Input: []float64{1, 0}, Response: []float64{0}},
+	}
+	e := NewEarlyStopping(MetricLoss, 3, 0)
+	trainer := NewBatchTrainer(NewSGD(50, 0, 0, false), 0, 2, 1)
+	trainer.SetCallbacks(e)
+	assert.NoError(t, trainer.Train(n, examples, examples, 1000))
+
+	assert.Greater(t, e.Stopped, e.BestEpoch)
+	assert.Greater(t, e.BestEpoch, 0)
+	assert.InDelta(t, e.Best, crossValidate(n, examples), 1e-9)
+}
+
+func randomWeights(n *deep.Neural) [][][]float64 {
+	weights := n.Weights()
+	for i := range weights {
+		for j := range weights[i] {
+			for k := range weights[i][j] {
+				weights[i][j][k] = rand.NormFloat64()
+			}
+		}
+	}
+	return weights
+}
@@ -0,0 +1,112 @@
+package training
+
+import (
+	"math"
+
+	deep "github.com/patrikeh/go-deep"
+)
+
+// GradientReport holds the max relative error per layer between
+// backpropagated gradients and their central difference estimates
+type GradientReport struct {
+	Online []float64
+	Batch  []float64
+}
+
+// Max returns the largest relative error in the report
+func (r GradientReport) Max() float64 {
+	var m float64
+	for _, errs := range [][]float64{r.Online, r.Batch} {
+		for _, e := range errs {
+			m = math.Max(m, e)
+		}
+	}
+	return m
+}
+
+// GradientCheck compares the gradients computed by the online and batch
+// trainers against central difference estimates of the loss summed over
+// examples, perturbing each weight by ±epsilon
+func GradientCheck(n *deep.Neural, examples Examples, epsilon float64) GradientReport {
+	epsilon = fparam(epsilon, 1e-6)
+	numerical := numericalGradients(n, examples, epsilon)
+
+	online := NewTrainer(nil, 0)
+	online.internal = newTraining(n.Layers)
+	batch := NewBatchTrainer(nil, 0, 1, 1)
+	batch.internalb = newBatchTraining(n.Layers, 1)
+
+	return GradientReport{
+		Online: relativeErrors(online.gradients(n, examples), numerical),
+		Batch:  relativeErrors(batch.gradients(n, examples), numerical),
+	}
+}
+
+func numericalGradients(n *deep.Neural, examples Examples, epsilon float64) [][][]float64 {
+	grads := n.Weights()
+	for i, l := range n.Layers {
+		for j, neuron := range l.Neurons {
+			for k, s := range neuron.In {
+				w := s.Weight
+				s.Weight = w + epsilon
+				plus := objective(n, examples)
+				s.Weight = w - epsilon
+				minus := objective(n, examples)
+				s.Weight = w
+				grads[i][j][k] = (plus - minus) / (2 * epsilon)
+			}
+		}
+	}
+	return grads
+}
+
+// objective is the weighted loss summed over examples, as differentiated by
+// the trainers, with targets in the space of the TargetScaler
+func objective(n *deep.Neural, examples Examples) float64 {
+	var sum float64
+	for _, e := range examples {
+		n.Forward(e.Input)
+		sum += exampleLoss(n, e)
+	}
+	return sum
+}
+
+// exampleLoss is the weighted loss of e on the output of the last forward pass
+func exampleLoss(n *deep.Neural, e Example) float64 {
+	out := n.Layers[len(n.Layers)-1].Neurons
+	estimate := make([]float64, len(out))
+	for i, neuron := range out {
+		estimate[i] = neuron.Value
+	}
+	ideal := n.Config.TargetScaler.Transform(e.Response)
+
+	var sum float64
+	var start int
+	for _, h := range n.Config.OutputHeads() {
+		end := start + h.Outputs
+		sum += weight(n, e) * h.Weight * headObjective(n.Config.HeadLoss(h), estimate[start:end], ideal[start:end])
+		start = end
+	}
+	return sum
+}
+
+func headObjective(loss deep.Loss, estimate, ideal []float64) float64 {
+	if o, ok := loss.(deep.Objective); ok {
+		return o.Objective(estimate, ideal)
+	}
+	return loss.F([][]float64{estimate}, [][]float64{ideal})
+}
+
+func relativeErrors(analytic, numerical [][][]float64) []float64 {
+	errs := make([]float64, len(analytic))
+	for i := range analytic {
+		for j := range analytic[i] {
+			for k := range analytic[i][j] {
+				a, n := analytic[i][j][k], numerical[i][j][k]
+				scale := math.Max(math.Max(math.Abs(a), math.Abs(n)), 1e-6)
+				errs[i] = math.Max(errs[i], math.Abs(a-n)/scale)
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,134 @@
+package training
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	modes       = []deep.Mode{deep.ModeDefault, deep.ModeMultiClass, deep.ModeRegression, deep.ModeBinary, deep.ModeMultiLabel}
+	activations = []deep.ActivationType{deep.ActivationSigmoid, deep.ActivationTanh, deep.ActivationReLU, deep.ActivationLinear}
+	losses      = []deep.LossType{
+		deep.LossCrossEntropy, deep.LossBinaryCrossEntropy, deep.LossMeanSquared,
+		deep.LossHuber, deep.LossMeanAbsolute, deep.LossHinge, deep.LossFocal,
+		deep.LossKLDivergence, deep.LossSmoothedCrossEntropy,
+	}
+)
+
+func Test_GradientCheck(t *testing.T) {
+	examples := Examples{
+		{Input: []float64{0.1, 0.5, -0.3}, Response: []float64{1, 0, 0}},
+		{Input: []float64{-0.7, 0.2, 0.9}, Response: []float64{0, 0, 1}},
+		{Input: []float64{0.4, -0.8, 0.6}, Response: []float64{0, 1, 0}},
+	}
+
+	for _, mode := range modes {
+		for _, act := range activations {
+			for _, loss := range losses {
+				for _, bias := range []bool{true, false} {
+					rand.Seed(1)
+					n := deep.NewNeural(&deep.Config{
+						Inputs:     3,
+						Layout:     []int{4, 3, 3},
+						Activation: act,
+						Mode:       mode,
+						Loss:       loss,
+						Weight:     deep.NewNormal(0.5, 0.1),
+						Bias:       bias,
+					})
+					name := fmt.Sprintf("mode: %s activation: %s loss: %s bias: %v", mode, act, loss, bias)
+
+					report := GradientCheck(n, examples, 1e-6)
+					assert.Len(t, report.Online, len(n.Layers))
+					assert.Len(t, report.Batch, len(n.Layers))
+					_, canonical := deep.GetLoss(loss).(deep.Canonical)
+					switch {
+					case n.Config.Validate() == nil:
+						assert.Less(t, report.Max(), 1e-4, name)
+					case canonical:
+						assert.False(t, report.Max() < 1e-2, "mismatch not detected for "+name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func Test_GradientCheckRestoresWeights(t *testing.T) {
+	rand.Seed(0)
+	n := deep.NewNeural(&deep.Config{
+		Inputs:     2,
+		Layout:     []int{3, 1},
+		Activation: deep.ActivationTanh,
+		Mode:       deep.ModeBinary,
+		Bias:       true,
+	})
+	weights := n.Weights()
+	GradientCheck(n, data, 0)
+	assert.Equal(t, weights, n.Weights())
+}
+
+func Test_WeightedGradients(t *testing.T) {
+	rand.Seed(0)
+	n := deep.NewNeural(&deep.Config{
+		Inputs:       2,
+		Layout:       []int{3, 2},
+		Activation:   deep.ActivationTanh,
+		Mode:         deep.ModeMultiClass,
+		Bias:         true,
+		ClassWeights: []float64{0.5, 3},
+	})
+	weighted := Examples{
+		{Input: []float64{0.5, -0.1}, Response: []float64{1, 0}, Weight: 2},
+		{Input: []float64{-0.3, 0.8}, Response: []float64{0, 1}},
+	}
+	assert.Less(t, GradientCheck(n, weighted, 0).Max(), 1e-4)
+
+	n.Config.ClassWeights = nil
+	duplicated := Examples{weighted[0], weighted[0], weighted[1]}
+	duplicated[0].Weight, duplicated[1].Weight = 1, 1
+
+	trainer := NewTrainer(nil, 0)
+	trainer.internal = newTraining(n.Layers)
+	expected := trainer.gradients(n, duplicated)
+	actual := trainer.gradients(n, weighted)
+	for i := range expected {
+		for j := range expected[i] {
+			assert.InDeltaSlice(t, expected[i][j], actual[i][j], 1e-12)
+		}
+	}
+	assert.InDelta(t, crossValidate(n, duplicated), crossValidate(n, weighted), 1e-12)
+}
+
+func Test_ScaledGradientCheck(t *testing.T) {
+	examples := Examples{
+		{Input: []float64{10, 500}, Response: []float64{-20, 300}},
+		{Input: []float64{-30, 200}, Response: []float64{40, 100}},
+		{Input: []float64{20, 800}, Response: []float64{10, 700}},
+	}
+	inputs, targets := deep.NewScaler(deep.ScaleStandard), deep.NewScaler(deep.ScaleRobust)
+	assert.NoError(t, inputs.Fit(examples.Inputs()))
+	assert.NoError(t, targets.Fit(examples.Responses()))
+
+	rand.Seed(1)
+	n := deep.NewNeural(&deep.Config{
+		Inputs:       2,
+		Layout:       []int{4, 2},
+		Activation:   deep.ActivationTanh,
+		Mode:         deep.ModeRegression,
+		Weight:       deep.NewNormal(0.5, 0.1),
+		Bias:         true,
+		InputScaler:  inputs,
+		TargetScaler: targets,
+	})
+	assert.Less(t, GradientCheck(n, examples, 1e-6).Max(), 1e-4)
+
+	assert.NoError(t, NewBatchTrainer(NewAdam(0.05, 0.9, 0.999, 1e-8), 0, 3, 1).Train(n, examples, nil, 2000))
+	for _, e := range examples {
+		assert.InDeltaSlice(t, e.Response, n.Predict(e.Input), 5)
+	}
+}
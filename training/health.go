@@ -0,0 +1,201 @@
+package training
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+
+	deep "github.com/patrikeh/go-deep"
+)
+
+// HealthPolicy determines how trainers react to NaN or infinite values
+// in forward passes, gradients and weight updates
+type HealthPolicy int
+
+const (
+	// HealthError stops training and returns the error
+	HealthError HealthPolicy = 0
+	// HealthPanic panics with the error
+	HealthPanic HealthPolicy = 1
+	// HealthSkip discards the offending batch and continues training
+	HealthSkip HealthPolicy = 2
+	// HealthHalt stops training without returning an error
+	HealthHalt HealthPolicy = 3
+)
+
+// Health summarizes the numeric health of the last training run
+type Health struct {
+	// First is the first non-finite value encountered, nil if none was
+	First *deep.NumericError
+	// Epoch in which First was encountered
+	Epoch int
+	// Skipped is the number of batches discarded under HealthSkip
+	Skipped int
+}
+
+// Diverged reports whether a non-finite value was encountered
+func (h Health) Diverged() bool {
+	return h.First != nil
+}
+
+type monitor struct {
+	policy HealthPolicy
+	health Health
+}
+
+// SetHealthPolicy sets how non-finite values are handled, HealthError by default
+func (m *monitor) SetHealthPolicy(policy HealthPolicy) {
+	m.policy = policy
+}
+
+// Health returns the numeric health of the last training run
+func (m *monitor) Health() Health {
+	return m.health
+}
+
+// check applies the health policy to err, returning whether to stop training
+// and the error to return. Errors other than *deep.NumericError always stop.
+func (m *monitor) check(err error, epoch int) (bool, error) {
+	var numeric *deep.NumericError
+	if !errors.As(err, &numeric) {
+		return true, err
+	}
+	if m.health.First == nil {
+		m.health.First, m.health.Epoch = numeric, epoch
+	}
+	err = fmt.Errorf("training diverged in epoch %d: %w", epoch, err)
+	switch m.policy {
+	case HealthPanic:
+		panic(err)
+	case HealthSkip:
+		m.health.Skipped++
+		return false, nil
+	case HealthHalt:
+		return true, nil
+	}
+	return true, err
+}
+
+// checkDeltas returns an error for the first non-finite neuron delta
+func checkDeltas(deltas [][]float64) error {
+	for i := range deltas {
+		for j, d := range deltas[i] {
+			if !deep.IsFinite(d) {
+				return nonFinite(deep.StageGradient, d, i, j, -1)
+			}
+		}
+	}
+	return nil
+}
+
+func nonFinite(stage deep.NumericStage, value float64, layer, neuron, synapse int) *deep.NumericError {
+	return &deep.NumericError{Stage: stage, Layer: layer, Neuron: neuron, Synapse: synapse, Value: value}
+}
+
+// vectored is implemented by solvers whose state is a set of vectors indexed
+// like the weights, which steps save into a reused buffer before updating
+type vectored interface {
+	vectors() [][]float64
+}
+
+// stepper applies all or nothing solver steps, reusing its buffers
+type stepper struct {
+	// updates holds one value per weight
+	updates []float64
+	// undo holds the solver's vectors before the current step
+	undo []float64
+}
+
+func newStepper(weights int) stepper {
+	return stepper{updates: make([]float64, weights)}
+}
+
+// apply updates the trainable weights of n by the solver, with the gradient of
+// each weight given by gradient. It is all or nothing: if a gradient or update
+// is not finite, no weight changes and the solver's state is restored.
+// Non-finite gradients are rejected before the solver runs. The state of
+// vectored solvers is restored from the undo buffer. Other Stateful solvers
+// are snapshotted only if skip is set, as other policies stop training.
+func (st *stepper) apply(n *deep.Neural, solver Solver, it int, d *Diagnostics, skip bool, gradient func(i, j, k int) float64) error {
+	updates := st.updates
+	var err *deep.NumericError
+	var idx int
+	for i, l := range n.Layers {
+		for j, neuron := range l.Neurons {
+			for k, s := range neuron.In {
+				if s.Trainable() {
+					g := gradient(i, j, k)
+					d.observe(i, g)
+					if !deep.IsFinite(g) {
+						err = cmp.Or(err, nonFinite(deep.StageGradient, g, i, j, k))
+					}
+					updates[idx] = g
+				}
+				idx++
+			}
+		}
+	}
+	d.endStep()
+	if err != nil {
+		return err
+	}
+
+	var vectors [][]float64
+	var saved map[string][]float64
+	if v, ok := solver.(vectored); ok {
+		vectors = v.vectors()
+		st.save(vectors)
+	} else if s, ok := solver.(Stateful); ok && skip {
+		saved = s.State()
+	}
+	idx = 0
+	for i, l := range n.Layers {
+		for j, neuron := range l.Neurons {
+			for k, s := range neuron.In {
+				if s.Trainable() {
+					updates[idx] = solver.Update(s.Weight, updates[idx], it, idx)
+					if !deep.IsFinite(updates[idx]) {
+						err = cmp.Or(err, nonFinite(deep.StageUpdate, updates[idx], i, j, k))
+					}
+				}
+				idx++
+			}
+		}
+	}
+	if err != nil {
+		st.restore(vectors)
+		if saved != nil {
+			solver.(Stateful).SetState(saved)
+		}
+		return err
+	}
+
+	idx = 0
+	for _, l := range n.Layers {
+		for _, neuron := range l.Neurons {
+			for _, s := range neuron.In {
+				if s.Trainable() {
+					s.Weight += updates[idx]
+				}
+				idx++
+			}
+		}
+	}
+	return nil
+}
+
+// save copies vectors into the undo buffer
+func (st *stepper) save(vectors [][]float64) {
+	st.undo = st.undo[:0]
+	for _, v := range vectors {
+		st.undo = append(st.undo, v...)
+	}
+}
+
+// restore copies the undo buffer back into vectors
+func (st *stepper) restore(vectors [][]float64) {
+	undo := st.undo
+	for _, v := range vectors {
+		undo = undo[copy(v, undo):]
+	}
+}
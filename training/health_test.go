@@ -0,0 +1,194 @@
+package training
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func healthNetwork() *deep.Neural {
+	return deep.NewNeural(&deep.Config{
+		Inputs:     2,
+		Layout:     []int{3, 1},
+		Activation: deep.ActivationSigmoid,
+		Mode:       deep.ModeBinary,
+		Weight:     deep.NewNormal(1, 0),
+		Bias:       true,
+	})
+}
+
+func healthExamples() Examples {
+	return Examples{
+		{Input: []float64{0, 1}, Response: []float64{1}},
+		{Input: []float64{1, 0}, Response: []float64{1}},
+		{Input: []float64{math.NaN(), 1}, Response: []float64{0}},
+		{Input: []float64{1, 1}, Response: []float64{0}},
+	}
+}
+
+type monitoredTrainer interface {
+	Trainer
+	SetHealthPolicy(HealthPolicy)
+	Health() Health
+}
+
+func healthTrainers() map[string]monitoredTrainer {
+	return map[string]monitoredTrainer{
+		"online": NewTrainer(NewSGD(0.1, 0, 0, false), 0),
+		"batch":  NewBatchTrainer(NewSGD(0.1, 0, 0, false), 0, 1, 2),
+	}
+}
+
+func Test_HealthError(t *testing.T) {
+	for name, trainer := range healthTrainers() {
+		err := trainer.Train(healthNetwork(), healthExamples(), nil, 3)
+
+		var numeric *deep.NumericError
+		assert.True(t, errors.As(err, &numeric), name)
+		assert.Equal(t, deep.StageForward, numeric.Stage, name)
+		assert.Equal(t, 0, numeric.Layer, name)
+		assert.True(t, trainer.Health().Diverged(), name)
+		assert.Equal(t, 1, trainer.Health().Epoch, name)
+	}
+}
+
+func Test_HealthSkip(t *testing.T) {
+	for name, trainer := range healthTrainers() {
+		n := healthNetwork()
+		trainer.SetHealthPolicy(HealthSkip)
+		assert.NoError(t, trainer.Train(n, healthExamples(), nil, 3), name)
+		assert.Equal(t, 3, trainer.Health().Skipped, name)
+
+		for _, w := range n.Weights() {
+			for _, ws := range w {
+				for _, v := range ws {
+					assert.True(t, deep.IsFinite(v), name)
+				}
+			}
+		}
+	}
+}
+
+func Test_HealthHalt(t *testing.T) {
+	for name, trainer := range healthTrainers() {
+		trainer.SetHealthPolicy(HealthHalt)
+		assert.NoError(t, trainer.Train(healthNetwork(), healthExamples(), nil, 3), name)
+		assert.True(t, trainer.Health().Diverged(), name)
+	}
+}
+
+func Test_HealthPanic(t *testing.T) {
+	for name, trainer := range healthTrainers() {
+		trainer.SetHealthPolicy(HealthPanic)
+		assert.Panics(t, func() { trainer.Train(healthNetwork(), healthExamples(), nil, 1) }, name)
+	}
+}
+
+func Test_HealthUpdate(t *testing.T) {
+	n := healthNetwork()
+	trainer := NewTrainer(NewSGD(math.Inf(1), 0, 0, false), 0)
+	err := trainer.Train(n, Examples{{Input: []float64{1, 1}, Response: []float64{0}}}, nil, 1)
+
+	var numeric *deep.NumericError
+	assert.True(t, errors.As(err, &numeric))
+	assert.Equal(t, deep.StageUpdate, numeric.Stage)
+}
+
+// opaqueSolver is a Stateful solver whose vectors are unknown to trainers
+type opaqueSolver struct {
+	Solver
+	Stateful
+}
+
+func Test_HealthStepIsAtomic(t *testing.T) {
+	sgd := NewSGD(10, 0.9, 0, false)
+	for name, tc := range map[string]struct {
+		solver interface {
+			Solver
+			Stateful
+		}
+		stage  deep.NumericStage
+		inject float64
+	}{
+		"gradient": {NewAdam(0.1, 0, 0, 0), deep.StageGradient, math.NaN()},
+		"update":   {NewSGD(10, 0.9, 0, false), deep.StageUpdate, math.MaxFloat64},
+		"stateful": {opaqueSolver{sgd, sgd}, deep.StageUpdate, math.MaxFloat64},
+	} {
+		n := healthNetwork()
+		solver := tc.solver
+		solver.Init(n.NumWeights())
+		trainer := NewBatchTrainer(solver, 0, 1, 1)
+		trainer.SetHealthPolicy(HealthSkip)
+		trainer.internalb = newBatchTraining(n.Layers, 1)
+
+		fill := func(v float64) {
+			for _, iAD := range trainer.accumulatedDeltas {
+				for _, jAD := range iAD {
+					for k := range jAD {
+						jAD[k] = v
+					}
+				}
+			}
+		}
+		fill(1)
+		assert.NoError(t, trainer.update(n, 1), name)
+
+		weights, state := n.Weights(), solver.State()
+		fill(1)
+		trainer.accumulatedDeltas[1][0][2] = tc.inject
+		err := trainer.update(n, 2)
+
+		var numeric *deep.NumericError
+		assert.True(t, errors.As(err, &numeric), name)
+		assert.Equal(t, tc.stage, numeric.Stage, name)
+		assert.Equal(t, weights, n.Weights(), name)
+		assert.Equal(t, state, solver.State(), name)
+	}
+
+	n := healthNetwork()
+	solver := NewSGD(0.1, 0.9, 0, false)
+	solver.Init(n.NumWeights())
+	trainer := NewTrainer(solver, 0)
+	trainer.internal = newTraining(n.Layers)
+	e := Example{Input: []float64{1, 1}, Response: []float64{0}}
+	n.Forward(e.Input)
+	trainer.calculateDeltas(n, e)
+	assert.NoError(t, trainer.update(n, 1))
+
+	weights, state := n.Weights(), solver.State()
+	n.Layers[1].Neurons[0].In[2].In = math.NaN()
+	assert.Error(t, trainer.update(n, 2))
+	assert.Equal(t, weights, n.Weights())
+	assert.Equal(t, state, solver.State())
+}
+
+func Benchmark_OnlineStep(b *testing.B) {
+	rand.Seed(0)
+	n := deep.NewNeural(&deep.Config{
+		Inputs:     64,
+		Layout:     []int{64, 10},
+		Activation: deep.ActivationReLU,
+		Mode:       deep.ModeMultiClass,
+		Weight:     deep.NewNormal(0.1, 0),
+		Bias:       true,
+	})
+	e := Example{Input: make([]float64, 64), Response: make([]float64, 10)}
+	for i := range e.Input {
+		e.Input[i] = rand.Float64()
+	}
+	e.Response[3] = 1
+
+	solver := NewAdam(0.001, 0.9, 0.999, 1e-8)
+	solver.Init(n.NumWeights())
+	trainer := NewTrainer(solver, 0)
+	trainer.internal = newTraining(n.Layers)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trainer.learn(n, e, 1)
+	}
+}
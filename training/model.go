@@ -1,16 +1,40 @@
 package training
 
-import "math/rand"
+import (
+	"math/rand"
+
+	deep "github.com/patrikeh/go-deep"
+)
 
 // Example is an input-target pair
 type Example struct {
 	Input    []float64
 	Response []float64
+	// Weight scales the example's contribution to the loss, 0 counts as 1
+	Weight float64
 }
 
 // Examples is a set of input-output pairs
 type Examples []Example
 
+// Inputs returns the inputs of e, e.g. for fitting a deep.Scaler
+func (e Examples) Inputs() [][]float64 {
+	inputs := make([][]float64, len(e))
+	for i := range e {
+		inputs[i] = e[i].Input
+	}
+	return inputs
+}
+
+// Responses returns the responses of e
+func (e Examples) Responses() [][]float64 {
+	responses := make([][]float64, len(e))
+	for i := range e {
+		responses[i] = e[i].Response
+	}
+	return responses
+}
+
 // Shuffle shuffles slice in-place
 func (e Examples) Shuffle() {
 	for i := range e {
@@ -50,6 +74,50 @@ func (e Examples) SplitN(n int) []Examples {
 	return res
 }
 
+// BalancedClassWeights returns class weights inversely proportional to
+// class frequencies, for use as deep.Config.ClassWeights
+func (e Examples) BalancedClassWeights() []float64 {
+	if len(e) == 0 {
+		return nil
+	}
+	classes := len(e[0].Response)
+	if classes == 1 {
+		classes = 2
+	}
+	counts := make([]float64, classes)
+	for _, ex := range e {
+		if k := class(ex.Response); k >= 0 && k < classes {
+			counts[k]++
+		}
+	}
+	weights := make([]float64, classes)
+	for i, c := range counts {
+		weights[i] = 1
+		if c > 0 {
+			weights[i] = float64(len(e)) / (float64(classes) * c)
+		}
+	}
+	return weights
+}
+
+// weight is the sample weight of e scaled by the class weight of its target.
+// Targets outside the class weights, possible in unvalidated configs, are not scaled.
+func weight(n *deep.Neural, e Example) float64 {
+	w := fparam(e.Weight, 1)
+	if k := class(e.Response); k >= 0 && k < len(n.Config.ClassWeights) {
+		w *= n.Config.ClassWeights[k]
+	}
+	return w
+}
+
+// class is the class of a one-hot target, or 0/1 for a single binary target
+func class(response []float64) int {
+	if len(response) == 1 {
+		return int(deep.Round(response[0]))
+	}
+	return deep.ArgMax(response)
+}
+
 func min(a, b int) int {
 	if a <= b {
 		return a
@@ -4,6 +4,7 @@ import (
 	"math/rand"
 	"testing"
 
+	deep "github.com/patrikeh/go-deep"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +38,26 @@ func Test_Split(t *testing.T) {
 	assert.InEpsilon(t, len(a), 50, 0.1)
 	assert.InEpsilon(t, len(b), 50, 0.1)
 }
+
+func Test_BalancedClassWeights(t *testing.T) {
+	e := Examples{
+		{Response: []float64{1, 0, 0}},
+		{Response: []float64{1, 0, 0}},
+		{Response: []float64{1, 0, 0}},
+		{Response: []float64{0, 1, 0}},
+	}
+	assert.Equal(t, []float64{4.0 / 9, 4.0 / 3, 1}, e.BalancedClassWeights())
+
+	binary := Examples{{Response: []float64{0}}, {Response: []float64{0}}, {Response: []float64{0}}, {Response: []float64{1}}}
+	assert.Equal(t, []float64{4.0 / 6, 2}, binary.BalancedClassWeights())
+}
+
+func Test_WeightOutOfRange(t *testing.T) {
+	n := deep.NewNeural(&deep.Config{Inputs: 1, Layout: []int{1}, Mode: deep.ModeRegression, ClassWeights: []float64{2, 3}})
+	assert.Equal(t, 3.0, weight(n, Example{Response: []float64{1}}))
+	assert.Equal(t, 1.0, weight(n, Example{Response: []float64{3.7}}))
+	assert.Equal(t, 1.0, weight(n, Example{Response: []float64{-2}}))
+
+	e := Examples{{Response: []float64{0}}, {Response: []float64{1}}, {Response: []float64{3.7}}}
+	assert.Len(t, e.BalancedClassWeights(), 2)
+}
@@ -3,6 +3,7 @@ package training
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
@@ -12,57 +13,116 @@ import (
 // StatsPrinter prints training progress
 type StatsPrinter struct {
 	w *tabwriter.Writer
+	// rate is the learning rate per epoch of scheduled solvers
+	rate func(epoch int) float64
 }
 
 // NewStatsPrinter creates a StatsPrinter
 func NewStatsPrinter() *StatsPrinter {
-	return &StatsPrinter{tabwriter.NewWriter(os.Stdout, 16, 0, 3, ' ', 0)}
+	return &StatsPrinter{w: tabwriter.NewWriter(os.Stdout, 16, 0, 3, ' ', 0)}
 }
 
 // Init initializes printer
 func (p *StatsPrinter) Init(n *deep.Neural) {
-	fmt.Fprintf(p.w, "Epochs\tElapsed\tLoss (%s)\t", n.Config.Loss)
-	if n.Config.Mode == deep.ModeMultiClass {
-		fmt.Fprintf(p.w, "Accuracy\t\n---\t---\t---\t---\t\n")
-	} else {
-		fmt.Fprintf(p.w, "\n---\t---\t---\t\n")
+	columns := 2
+	fmt.Fprintf(p.w, "Epochs\tElapsed\t")
+	for _, h := range n.Config.OutputHeads() {
+		fmt.Fprintf(p.w, "%sLoss (%s)\t", headPrefix(h), h.Loss)
+		columns++
+		if h.Mode == deep.ModeMultiClass {
+			fmt.Fprintf(p.w, "%sAccuracy\t", headPrefix(h))
+			columns++
+		}
+	}
+	if p.rate != nil {
+		fmt.Fprintf(p.w, "Learning rate\t")
+		columns++
 	}
+	fmt.Fprintf(p.w, "\n%s\n", strings.Repeat("---\t", columns))
 }
 
-// PrintProgress prints the current state of training
+// PrintProgress prints the current state of training, with loss and
+// accuracy reported per head for multi-head networks
 func (p *StatsPrinter) PrintProgress(n *deep.Neural, validation Examples, elapsed time.Duration, iteration int) {
-	fmt.Fprintf(p.w, "%d\t%s\t%.4f\t%s\n",
-		iteration,
-		elapsed.String(),
-		crossValidate(n, validation),
-		formatAccuracy(n, validation))
+	fmt.Fprintf(p.w, "%d\t%s\t", iteration, elapsed.String())
+	losses := headLosses(n, validation)
+	var start int
+	for i, h := range n.Config.OutputHeads() {
+		fmt.Fprintf(p.w, "%.4f\t", losses[i])
+		if h.Mode == deep.ModeMultiClass {
+			fmt.Fprintf(p.w, "%.2f\t", accuracy(n, validation, start, start+h.Outputs))
+		}
+		start += h.Outputs
+	}
+	if p.rate != nil {
+		fmt.Fprintf(p.w, "%.3g\t", p.rate(iteration))
+	}
+	fmt.Fprintln(p.w)
 	p.w.Flush()
 }
 
-func formatAccuracy(n *deep.Neural, validation Examples) string {
-	if n.Config.Mode == deep.ModeMultiClass {
-		return fmt.Sprintf("%.2f\t", accuracy(n, validation))
+func headPrefix(h deep.Head) string {
+	if h.Name == "" {
+		return ""
 	}
-	return ""
+	return h.Name + " "
 }
 
-func accuracy(n *deep.Neural, validation Examples) float64 {
+// accuracy is the fraction of examples whose outputs start to end have the correct argmax
+func accuracy(n *deep.Neural, validation Examples, start, end int) float64 {
 	correct := 0
 	for _, e := range validation {
 		est := n.Predict(e.Input)
-		if deep.ArgMax(e.Response) == deep.ArgMax(est) {
+		if deep.ArgMax(e.Response[start:end]) == deep.ArgMax(est[start:end]) {
 			correct++
 		}
 	}
 	return float64(correct) / float64(len(validation))
 }
 
+// crossValidate returns the validation loss, summing head losses by head weight
 func crossValidate(n *deep.Neural, validation Examples) float64 {
+	var sum float64
+	losses := headLosses(n, validation)
+	for i, h := range n.Config.OutputHeads() {
+		sum += h.Weight * losses[i]
+	}
+	return sum
+}
+
+// headLosses returns the validation loss of each head
+func headLosses(n *deep.Neural, validation Examples) []float64 {
 	predictions, responses := make([][]float64, len(validation)), make([][]float64, len(validation))
+	weighted := false
 	for i := 0; i < len(validation); i++ {
 		predictions[i] = n.Predict(validation[i].Input)
 		responses[i] = validation[i].Response
+		weighted = weighted || weight(n, validation[i]) != 1
 	}
 
-	return deep.GetLoss(n.Config.Loss).F(predictions, responses)
+	heads := n.Config.OutputHeads()
+	losses := make([]float64, len(heads))
+	var start int
+	for h, head := range heads {
+		end := start + head.Outputs
+		estimate, ideal := make([][]float64, len(validation)), make([][]float64, len(validation))
+		for i := range validation {
+			estimate[i], ideal[i] = predictions[i][start:end], responses[i][start:end]
+		}
+		start = end
+
+		loss := n.Config.HeadLoss(head)
+		if !weighted {
+			losses[h] = loss.F(estimate, ideal)
+			continue
+		}
+		var sum, total float64
+		for i, e := range validation {
+			w := weight(n, e)
+			sum += w * loss.F(estimate[i:i+1], ideal[i:i+1])
+			total += w
+		}
+		losses[h] = sum / total
+	}
+	return losses
 }
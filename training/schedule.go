@@ -0,0 +1,258 @@
+package training
+
+import (
+	"math"
+
+	deep "github.com/patrikeh/go-deep"
+)
+
+// Schedule determines the learning rate of each epoch from a solver's base rate
+type Schedule interface {
+	Rate(base float64, epoch int) float64
+}
+
+// ScheduleFunc adapts a function to a Schedule
+type ScheduleFunc func(base float64, epoch int) float64
+
+// Rate returns f(base, epoch)
+func (f ScheduleFunc) Rate(base float64, epoch int) float64 {
+	return f(base, epoch)
+}
+
+// Scheduled is implemented by solvers that follow a learning rate schedule
+type Scheduled interface {
+	SetSchedule(s Schedule)
+	Schedule() Schedule
+	// LearningRate is the learning rate used in the given iteration
+	LearningRate(iteration int) float64
+}
+
+type scheduling struct {
+	schedule  Schedule
+	iteration int
+	rate      float64
+}
+
+// SetSchedule sets the learning rate schedule, nil keeps the rate constant
+func (s *scheduling) SetSchedule(schedule Schedule) {
+	s.schedule = schedule
+	s.iteration = 0
+}
+
+// Schedule returns the learning rate schedule
+func (s *scheduling) Schedule() Schedule {
+	return s.schedule
+}
+
+// scheduled returns the scheduled rate of iteration, computed once per iteration
+func (s *scheduling) scheduled(base float64, iteration int) float64 {
+	if s.schedule == nil {
+		return base
+	}
+	if iteration != s.iteration {
+		s.iteration, s.rate = iteration, s.schedule.Rate(base, iteration)
+	}
+	return s.rate
+}
+
+// reset forgets the cached rate, at the start of a training run
+func (s *scheduling) reset() {
+	s.iteration = 0
+}
+
+// StepDecay multiplies the rate by Drop every Every epochs
+type StepDecay struct {
+	Drop  float64
+	Every int
+}
+
+// Rate returns the rate of epoch
+func (s StepDecay) Rate(base float64, epoch int) float64 {
+	return base * math.Pow(s.Drop, float64((epoch-1)/iparam(s.Every, 1)))
+}
+
+// ExponentialDecay multiplies the rate by Gamma every epoch
+type ExponentialDecay struct {
+	Gamma float64
+}
+
+// Rate returns the rate of epoch
+func (s ExponentialDecay) Rate(base float64, epoch int) float64 {
+	return base * math.Pow(s.Gamma, float64(epoch-1))
+}
+
+// CosineRestarts anneals the rate from base to Min along a half cosine over
+// Period epochs, then restarts with the period multiplied by Mult (SGDR)
+type CosineRestarts struct {
+	Period int
+	Mult   float64
+	Min    float64
+}
+
+// Rate returns the rate of epoch
+func (s CosineRestarts) Rate(base float64, epoch int) float64 {
+	period, mult := float64(iparam(s.Period, 10)), math.Max(s.Mult, 1)
+	t := float64(epoch - 1)
+	for t >= period {
+		t -= period
+		period *= mult
+	}
+	return s.Min + (base-s.Min)*(1+math.Cos(math.Pi*t/period))/2
+}
+
+// Warmup increases the rate linearly to base over Epochs epochs,
+// then follows Then, starting from its first epoch
+type Warmup struct {
+	Epochs int
+	Then   Schedule
+}
+
+// Rate returns the rate of epoch
+func (s Warmup) Rate(base float64, epoch int) float64 {
+	if epoch <= s.Epochs {
+		return base * float64(epoch) / float64(s.Epochs)
+	}
+	if s.Then == nil {
+		return base
+	}
+	return s.Then.Rate(base, epoch-s.Epochs)
+}
+
+// OneCycle anneals the rate from base/Div up to base over the first Warmup
+// fraction of Epochs, then down to base/(Div·FinalDiv), along cosines
+type OneCycle struct {
+	Epochs   int
+	Warmup   float64
+	Div      float64
+	FinalDiv float64
+}
+
+// Rate returns the rate of epoch
+func (s OneCycle) Rate(base float64, epoch int) float64 {
+	start := base / fparam(s.Div, 25)
+	end := start / fparam(s.FinalDiv, 1e4)
+	up := math.Max(math.Round(fparam(s.Warmup, 0.3)*float64(s.Epochs)), 1)
+	t := float64(epoch - 1)
+	if t < up {
+		return anneal(start, base, t/up)
+	}
+	down := math.Max(float64(s.Epochs)-up-1, 1)
+	return anneal(base, end, math.Min((t-up)/down, 1))
+}
+
+// anneal moves from start to end along a half cosine as progress goes from 0 to 1
+func anneal(start, end, progress float64) float64 {
+	return end + (start-end)*(1+math.Cos(math.Pi*progress))/2
+}
+
+// ReduceOnPlateau multiplies the rate by Factor once the validation loss
+// has not improved by more than MinDelta for Patience epochs, down to Min.
+// Trainers pass it the validation loss when it is their solver's schedule,
+// and checkpoints save its state. Nested in another schedule, such as
+// Warmup, it is neither driven nor checkpointed: it must then be added to
+// the trainer's callbacks, and resumed runs start it over.
+type ReduceOnPlateau struct {
+	NopCallback
+	Factor   float64
+	Patience int
+	MinDelta float64
+	Min      float64
+
+	reductions int
+	best       float64
+	wait       int
+}
+
+// NewReduceOnPlateau returns a ReduceOnPlateau, factor defaults to 0.1 and patience to 10 epochs
+func NewReduceOnPlateau(factor float64, patience int, minDelta, min float64) *ReduceOnPlateau {
+	return &ReduceOnPlateau{
+		Factor:   fparam(factor, 0.1),
+		Patience: iparam(patience, 10),
+		MinDelta: minDelta,
+		Min:      min,
+		best:     math.Inf(1),
+	}
+}
+
+// Rate returns the reduced rate
+func (s *ReduceOnPlateau) Rate(base float64, epoch int) float64 {
+	return math.Max(base*math.Pow(fparam(s.Factor, 0.1), float64(s.reductions)), s.Min)
+}
+
+// State returns the number of reductions and the progress towards the next.
+// An infinite best loss, before any validation, is left out.
+func (s *ReduceOnPlateau) State() map[string][]float64 {
+	saved := map[string][]float64{
+		"reductions": {float64(s.reductions)},
+		"wait":       {float64(s.wait)},
+	}
+	if !math.IsInf(s.best, 1) {
+		saved["best"] = []float64{s.best}
+	}
+	return saved
+}
+
+// SetState restores a state saved by State
+func (s *ReduceOnPlateau) SetState(saved map[string][]float64) error {
+	reductions, err := state(saved, "reductions", 1)
+	if err != nil {
+		return err
+	}
+	wait, err := state(saved, "wait", 1)
+	if err != nil {
+		return err
+	}
+	s.reductions, s.wait, s.best = int(reductions[0]), int(wait[0]), math.Inf(1)
+	if _, ok := saved["best"]; ok {
+		best, err := state(saved, "best", 1)
+		if err != nil {
+			return err
+		}
+		s.best = best[0]
+	}
+	return nil
+}
+
+// OnTrainBegin restores the base rate
+func (s *ReduceOnPlateau) OnTrainBegin(n *deep.Neural) {
+	s.reductions, s.best, s.wait = 0, math.Inf(1), 0
+}
+
+// OnEpochEnd reduces the rate once the validation loss stops improving
+func (s *ReduceOnPlateau) OnEpochEnd(n *deep.Neural, p Progress) bool {
+	if p.Validation == nil {
+		return false
+	}
+	if p.ValidationLoss < s.best-s.MinDelta {
+		s.best, s.wait = p.ValidationLoss, 0
+		return false
+	}
+	s.wait++
+	if s.wait >= iparam(s.Patience, 10) {
+		s.reductions++
+		s.wait = 0
+	}
+	return false
+}
+
+// schedule returns the solver's schedule, nil if it has none
+func schedule(solver Solver) Schedule {
+	if s, ok := solver.(Scheduled); ok {
+		return s.Schedule()
+	}
+	return nil
+}
+
+// scheduleCallback returns the solver's schedule if it is also a Callback
+func scheduleCallback(solver Solver) Callback {
+	c, _ := schedule(solver).(Callback)
+	return c
+}
+
+// learningRate returns the solver's learning rate per iteration if it has a schedule
+func learningRate(solver Solver) func(iteration int) float64 {
+	if s, ok := solver.(Scheduled); ok && s.Schedule() != nil {
+		return s.LearningRate
+	}
+	return nil
+}
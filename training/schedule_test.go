@@ -0,0 +1,91 @@
+package training
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/patrikeh/go-deep/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rates(s Schedule, epochs int) []float64 {
+	rates := make([]float64, epochs)
+	for i := range rates {
+		rates[i] = s.Rate(1, i+1)
+	}
+	return rates
+}
+
+func Test_Schedules(t *testing.T) {
+	assert.InDeltaSlice(t, []float64{1, 1, 0.5, 0.5, 0.25}, rates(StepDecay{Drop: 0.5, Every: 2}, 5), 1e-9)
+	assert.InDeltaSlice(t, []float64{1, 0.9, 0.81}, rates(ExponentialDecay{Gamma: 0.9}, 3), 1e-9)
+	assert.InDeltaSlice(t, []float64{1, 0.5, 1, 0.8535533905932737, 0.5, 0.14644660940672627, 1}, rates(CosineRestarts{Period: 2, Mult: 2}, 7), 1e-9)
+	assert.InDeltaSlice(t, []float64{1, 0.55, 1, 0.55}, rates(CosineRestarts{Period: 2, Min: 0.1}, 4), 1e-9)
+	assert.InDeltaSlice(t, []float64{0.25, 0.5, 0.75, 1, 1, 1, 0.5}, rates(Warmup{Epochs: 4, Then: StepDecay{Drop: 0.5, Every: 2}}, 7), 1e-9)
+	assert.InDeltaSlice(t, []float64{0.5, 1}, rates(ScheduleFunc(func(base float64, epoch int) float64 { return base * float64(epoch) / 2 }), 2), 1e-9)
+
+	cycle := rates(OneCycle{Epochs: 10}, 10)
+	assert.InDelta(t, 1.0/25, cycle[0], 1e-9)
+	assert.InDelta(t, 1.0, cycle[3], 1e-9)
+	assert.InDelta(t, 1.0/25/1e4, cycle[9], 1e-12)
+	for i := 1; i < 10; i++ {
+		if i <= 3 {
+			assert.Greater(t, cycle[i], cycle[i-1])
+		} else {
+			assert.Less(t, cycle[i], cycle[i-1])
+		}
+	}
+}
+
+func Test_ReduceOnPlateau(t *testing.T) {
+	s := NewReduceOnPlateau(0.5, 2, 0, 0.2)
+	s.OnTrainBegin(nil)
+	for i, loss := range []float64{1, 0.9, 0.95, 0.92, 0.91, 0.93, 0.94, 0.99} {
+		s.OnEpochEnd(nil, Progress{Epoch: i + 1, ValidationLoss: loss, Validation: &metrics.Results{}})
+	}
+	assert.InDelta(t, 0.2, s.Rate(1, 9), 1e-9)
+	assert.Equal(t, 3, s.reductions)
+
+	s.OnTrainBegin(nil)
+	assert.Equal(t, 1.0, s.Rate(1, 1))
+}
+
+func Test_ScheduledTraining(t *testing.T) {
+	rand.Seed(0)
+	examples := healthExamples()[:2]
+
+	// nested in another schedule, it is only driven as a callback
+	plateau := NewReduceOnPlateau(0.5, 1, 0, 0)
+	solver := NewSGD(1e-300, 0, 0, false)
+	solver.SetSchedule(Warmup{Epochs: 1, Then: plateau})
+	trainer := NewTrainer(solver, 0)
+	trainer.SetCallbacks(plateau)
+	assert.NoError(t, trainer.Train(healthNetwork(), examples, examples, 4))
+	assert.Greater(t, plateau.reductions, 0)
+
+	var buf bytes.Buffer
+	adam := NewAdam(0.1, 0, 0, 0)
+	adam.SetSchedule(StepDecay{Drop: 0.5, Every: 1})
+	trainer = NewTrainer(adam, 1)
+	trainer.printer = &StatsPrinter{w: tabwriter.NewWriter(&buf, 16, 0, 3, ' ', 0)}
+	assert.NoError(t, trainer.Train(healthNetwork(), examples, examples, 3))
+	assert.Contains(t, buf.String(), "Learning rate")
+	assert.Contains(t, buf.String(), "0.025")
+	assert.InDelta(t, 0.025, adam.LearningRate(3), 1e-9)
+
+	// a schedule that is a callback is driven by the trainer
+	plateau = NewReduceOnPlateau(0.5, 1, 0, 0)
+	solver = NewSGD(1e-300, 0, 0, false)
+	solver.SetSchedule(plateau)
+	assert.NoError(t, NewBatchTrainer(solver, 0, 1, 1).Train(healthNetwork(), examples, examples, 4))
+	assert.Equal(t, 3, plateau.reductions)
+
+	// also set as a callback, it is driven once per epoch
+	batch := NewBatchTrainer(solver, 0, 1, 1)
+	batch.SetCallbacks(plateau)
+	assert.NoError(t, batch.Train(healthNetwork(), examples, examples, 4))
+	assert.Equal(t, 3, plateau.reductions)
+}
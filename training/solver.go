@@ -10,6 +10,7 @@ type Solver interface {
 
 // SGD is stochastic gradient descent with nesterov/momentum
 type SGD struct {
+	scheduling
 	lr       float64
 	decay    float64
 	momentum float64
@@ -30,23 +31,52 @@ func NewSGD(lr, momentum, decay float64, nesterov bool) *SGD {
 // Init initializes vectors using number of weights in network
 func (o *SGD) Init(size int) {
 	o.moments = make([]float64, size)
+	o.reset()
 }
 
-// Update returns the update for a given weight
+// LearningRate is the scheduled learning rate with inverse time decay
+func (o *SGD) LearningRate(iteration int) float64 {
+	return o.scheduled(o.lr, iteration) / (1 + o.decay*float64(iteration))
+}
+
+// Update returns the update for a given weight. With momentum μ, the velocity
+// is v = μv - lr·g and the update is v, or μv - lr·g with Nesterov momentum,
+// which takes the gradient step from the look-ahead position w + μv.
+// Neither rule depends on the weight itself.
 func (o *SGD) Update(value, gradient float64, iteration, idx int) float64 {
-	lr := o.lr / (1 + o.decay*float64(iteration))
+	lr := o.LearningRate(iteration)
 
 	o.moments[idx] = o.momentum*o.moments[idx] - lr*gradient
 
 	if o.nesterov {
-		o.moments[idx] = o.momentum*o.moments[idx] - lr*gradient
+		return o.momentum*o.moments[idx] - lr*gradient
 	}
 
 	return o.moments[idx]
 }
 
+// State returns a copy of the moments
+func (o *SGD) State() map[string][]float64 {
+	return map[string][]float64{"moments": append([]float64(nil), o.moments...)}
+}
+
+// SetState restores moments saved by State
+func (o *SGD) SetState(saved map[string][]float64) error {
+	moments, err := state(saved, "moments", len(o.moments))
+	if err != nil {
+		return err
+	}
+	o.moments = moments
+	return nil
+}
+
+func (o *SGD) vectors() [][]float64 {
+	return [][]float64{o.moments}
+}
+
 // Adam is an Adam solver
 type Adam struct {
+	scheduling
 	lr      float64
 	beta    float64
 	beta2   float64
@@ -68,11 +98,17 @@ func NewAdam(lr, beta, beta2, epsilon float64) *Adam {
 // Init initializes vectors using number of weights in network
 func (o *Adam) Init(size int) {
 	o.v, o.m = make([]float64, size), make([]float64, size)
+	o.reset()
+}
+
+// LearningRate is the scheduled learning rate
+func (o *Adam) LearningRate(iteration int) float64 {
+	return o.scheduled(o.lr, iteration)
 }
 
 // Update returns the update for a given weight
 func (o *Adam) Update(value, gradient float64, t, idx int) float64 {
-	lrt := o.lr * (math.Sqrt(1.0 - math.Pow(o.beta2, float64(t)))) /
+	lrt := o.LearningRate(t) * (math.Sqrt(1.0 - math.Pow(o.beta2, float64(t)))) /
 		(1.0 - math.Pow(o.beta, float64(t)))
 	o.m[idx] = o.beta*o.m[idx] + (1.0-o.beta)*gradient
 	o.v[idx] = o.beta2*o.v[idx] + (1.0-o.beta2)*math.Pow(gradient, 2.0)
@@ -80,6 +116,300 @@ func (o *Adam) Update(value, gradient float64, t, idx int) float64 {
 	return -lrt * (o.m[idx] / (math.Sqrt(o.v[idx]) + o.epsilon))
 }
 
+// State returns copies of the first and second moment estimates
+func (o *Adam) State() map[string][]float64 {
+	return map[string][]float64{
+		"m": append([]float64(nil), o.m...),
+		"v": append([]float64(nil), o.v...),
+	}
+}
+
+// SetState restores moment estimates saved by State
+func (o *Adam) SetState(saved map[string][]float64) error {
+	m, err := state(saved, "m", len(o.m))
+	if err != nil {
+		return err
+	}
+	v, err := state(saved, "v", len(o.v))
+	if err != nil {
+		return err
+	}
+	o.m, o.v = m, v
+	return nil
+}
+
+func (o *Adam) vectors() [][]float64 {
+	return [][]float64{o.m, o.v}
+}
+
+// RMSProp divides the learning rate by a moving average of squared gradients
+type RMSProp struct {
+	scheduling
+	lr      float64
+	rho     float64
+	epsilon float64
+
+	v []float64
+}
+
+// NewRMSProp returns a new RMSProp solver
+func NewRMSProp(lr, rho, epsilon float64) *RMSProp {
+	return &RMSProp{
+		lr:      fparam(lr, 0.001),
+		rho:     fparam(rho, 0.9),
+		epsilon: fparam(epsilon, 1e-8),
+	}
+}
+
+// Init initializes vectors using number of weights in network
+func (o *RMSProp) Init(size int) {
+	o.v = make([]float64, size)
+	o.reset()
+}
+
+// LearningRate is the scheduled learning rate
+func (o *RMSProp) LearningRate(iteration int) float64 {
+	return o.scheduled(o.lr, iteration)
+}
+
+// Update returns the update for a given weight
+func (o *RMSProp) Update(value, gradient float64, iteration, idx int) float64 {
+	o.v[idx] = o.rho*o.v[idx] + (1-o.rho)*gradient*gradient
+	return -o.LearningRate(iteration) * gradient / (math.Sqrt(o.v[idx]) + o.epsilon)
+}
+
+// State returns a copy of the squared gradient average
+func (o *RMSProp) State() map[string][]float64 {
+	return map[string][]float64{"v": append([]float64(nil), o.v...)}
+}
+
+// SetState restores the squared gradient average saved by State
+func (o *RMSProp) SetState(saved map[string][]float64) error {
+	v, err := state(saved, "v", len(o.v))
+	if err != nil {
+		return err
+	}
+	o.v = v
+	return nil
+}
+
+func (o *RMSProp) vectors() [][]float64 {
+	return [][]float64{o.v}
+}
+
+// Adagrad divides the learning rate by the root of the sum of squared gradients
+type Adagrad struct {
+	scheduling
+	lr      float64
+	epsilon float64
+
+	g []float64
+}
+
+// NewAdagrad returns a new Adagrad solver
+func NewAdagrad(lr, epsilon float64) *Adagrad {
+	return &Adagrad{
+		lr:      fparam(lr, 0.01),
+		epsilon: fparam(epsilon, 1e-8),
+	}
+}
+
+// Init initializes vectors using number of weights in network
+func (o *Adagrad) Init(size int) {
+	o.g = make([]float64, size)
+	o.reset()
+}
+
+// LearningRate is the scheduled learning rate
+func (o *Adagrad) LearningRate(iteration int) float64 {
+	return o.scheduled(o.lr, iteration)
+}
+
+// Update returns the update for a given weight
+func (o *Adagrad) Update(value, gradient float64, iteration, idx int) float64 {
+	o.g[idx] += gradient * gradient
+	return -o.LearningRate(iteration) * gradient / (math.Sqrt(o.g[idx]) + o.epsilon)
+}
+
+// State returns a copy of the summed squared gradients
+func (o *Adagrad) State() map[string][]float64 {
+	return map[string][]float64{"g": append([]float64(nil), o.g...)}
+}
+
+// SetState restores the summed squared gradients saved by State
+func (o *Adagrad) SetState(saved map[string][]float64) error {
+	g, err := state(saved, "g", len(o.g))
+	if err != nil {
+		return err
+	}
+	o.g = g
+	return nil
+}
+
+func (o *Adagrad) vectors() [][]float64 {
+	return [][]float64{o.g}
+}
+
+// Adadelta scales updates by the ratio of moving averages of squared
+// updates and squared gradients, the learning rate only scales the result
+type Adadelta struct {
+	scheduling
+	lr      float64
+	rho     float64
+	epsilon float64
+
+	g, d []float64
+}
+
+// NewAdadelta returns a new Adadelta solver
+func NewAdadelta(lr, rho, epsilon float64) *Adadelta {
+	return &Adadelta{
+		lr:      fparam(lr, 1),
+		rho:     fparam(rho, 0.95),
+		epsilon: fparam(epsilon, 1e-6),
+	}
+}
+
+// Init initializes vectors using number of weights in network
+func (o *Adadelta) Init(size int) {
+	o.g, o.d = make([]float64, size), make([]float64, size)
+	o.reset()
+}
+
+// LearningRate is the scheduled learning rate
+func (o *Adadelta) LearningRate(iteration int) float64 {
+	return o.scheduled(o.lr, iteration)
+}
+
+// Update returns the update for a given weight
+func (o *Adadelta) Update(value, gradient float64, iteration, idx int) float64 {
+	o.g[idx] = o.rho*o.g[idx] + (1-o.rho)*gradient*gradient
+	delta := -math.Sqrt(o.d[idx]+o.epsilon) / math.Sqrt(o.g[idx]+o.epsilon) * gradient
+	o.d[idx] = o.rho*o.d[idx] + (1-o.rho)*delta*delta
+	return o.LearningRate(iteration) * delta
+}
+
+// State returns copies of the squared gradient and update averages
+func (o *Adadelta) State() map[string][]float64 {
+	return map[string][]float64{
+		"g": append([]float64(nil), o.g...),
+		"d": append([]float64(nil), o.d...),
+	}
+}
+
+// SetState restores the averages saved by State
+func (o *Adadelta) SetState(saved map[string][]float64) error {
+	g, err := state(saved, "g", len(o.g))
+	if err != nil {
+		return err
+	}
+	d, err := state(saved, "d", len(o.d))
+	if err != nil {
+		return err
+	}
+	o.g, o.d = g, d
+	return nil
+}
+
+func (o *Adadelta) vectors() [][]float64 {
+	return [][]float64{o.g, o.d}
+}
+
+// AdamW is Adam with decoupled weight decay: weights shrink by the scheduled
+// learning rate times the decay, independently of the gradient moments
+type AdamW struct {
+	*Adam
+	weightDecay float64
+}
+
+// NewAdamW returns a new AdamW solver. A weight decay of 0 disables decay,
+// 0.01 is a common choice.
+func NewAdamW(lr, beta, beta2, epsilon, weightDecay float64) *AdamW {
+	return &AdamW{
+		Adam:        NewAdam(lr, beta, beta2, epsilon),
+		weightDecay: weightDecay,
+	}
+}
+
+// Update returns the update for a given weight
+func (o *AdamW) Update(value, gradient float64, t, idx int) float64 {
+	return o.Adam.Update(value, gradient, t, idx) - o.LearningRate(t)*o.weightDecay*value
+}
+
+// Nadam is Adam with Nesterov momentum, applying the bias-corrected
+// momentum of the next step
+type Nadam struct {
+	*Adam
+}
+
+// NewNadam returns a new Nadam solver
+func NewNadam(lr, beta, beta2, epsilon float64) *Nadam {
+	return &Nadam{NewAdam(lr, beta, beta2, epsilon)}
+}
+
+// Update returns the update for a given weight
+func (o *Nadam) Update(value, gradient float64, t, idx int) float64 {
+	o.m[idx] = o.beta*o.m[idx] + (1.0-o.beta)*gradient
+	o.v[idx] = o.beta2*o.v[idx] + (1.0-o.beta2)*gradient*gradient
+
+	m := o.beta*o.m[idx]/(1-math.Pow(o.beta, float64(t+1))) + (1-o.beta)*gradient/(1-math.Pow(o.beta, float64(t)))
+	v := o.v[idx] / (1 - math.Pow(o.beta2, float64(t)))
+	return -o.LearningRate(t) * m / (math.Sqrt(v) + o.epsilon)
+}
+
+// AMSGrad is Adam normalizing by the largest second moment estimate seen,
+// so that the effective learning rate never increases
+type AMSGrad struct {
+	*Adam
+	vmax []float64
+}
+
+// NewAMSGrad returns a new AMSGrad solver
+func NewAMSGrad(lr, beta, beta2, epsilon float64) *AMSGrad {
+	return &AMSGrad{Adam: NewAdam(lr, beta, beta2, epsilon)}
+}
+
+// Init initializes vectors using number of weights in network
+func (o *AMSGrad) Init(size int) {
+	o.Adam.Init(size)
+	o.vmax = make([]float64, size)
+}
+
+// Update returns the update for a given weight
+func (o *AMSGrad) Update(value, gradient float64, t, idx int) float64 {
+	o.m[idx] = o.beta*o.m[idx] + (1.0-o.beta)*gradient
+	o.v[idx] = o.beta2*o.v[idx] + (1.0-o.beta2)*gradient*gradient
+	o.vmax[idx] = math.Max(o.vmax[idx], o.v[idx])
+
+	m := o.m[idx] / (1 - math.Pow(o.beta, float64(t)))
+	v := o.vmax[idx] / (1 - math.Pow(o.beta2, float64(t)))
+	return -o.LearningRate(t) * m / (math.Sqrt(v) + o.epsilon)
+}
+
+// State returns copies of the moment estimates and the largest second moments
+func (o *AMSGrad) State() map[string][]float64 {
+	s := o.Adam.State()
+	s["vmax"] = append([]float64(nil), o.vmax...)
+	return s
+}
+
+// SetState restores the state saved by State
+func (o *AMSGrad) SetState(saved map[string][]float64) error {
+	vmax, err := state(saved, "vmax", len(o.vmax))
+	if err != nil {
+		return err
+	}
+	if err := o.Adam.SetState(saved); err != nil {
+		return err
+	}
+	o.vmax = vmax
+	return nil
+}
+
+func (o *AMSGrad) vectors() [][]float64 {
+	return [][]float64{o.m, o.v, o.vmax}
+}
+
 func fparam(val, fallback float64) float64 {
 	if val == 0.0 {
 		return fallback
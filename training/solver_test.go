@@ -0,0 +1,81 @@
+package training
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// descend minimizes (w-3)²/2 from w with one solver step per iteration
+func descend(solver Solver, w float64, steps int) []float64 {
+	solver.Init(1)
+	trajectory := make([]float64, steps)
+	for t := 1; t <= steps; t++ {
+		w += solver.Update(w, w-3, t, 0)
+		trajectory[t-1] = w
+	}
+	return trajectory
+}
+
+// Test_SolverTrajectories pins the weights of each solver minimizing (w-3)²/2.
+// The expected values were computed outside the repository from each solver's
+// published update rule. A change to any trajectory changes training and must
+// be deliberate.
+func Test_SolverTrajectories(t *testing.T) {
+	for name, tc := range map[string]struct {
+		solver   Solver
+		start    float64
+		expected []float64
+	}{
+		"sgd": {NewSGD(0.1, 0, 0, false), 1,
+			[]float64{1.2, 1.38, 1.5419999999999998, 1.6877999999999997, 1.8190199999999999}},
+		"sgd momentum": {NewSGD(0.1, 0.9, 0, false), 1,
+			[]float64{1.2, 1.56, 2.028, 2.5464, 3.05832}},
+		"sgd nesterov": {NewSGD(0.1, 0.9, 0, true), 1,
+			[]float64{1.38, 1.8498, 2.345358, 2.8122241800000003, 3.2091633678}},
+		"sgd decay": {NewSGD(0.1, 0.9, 0.5, false), 1,
+			[]float64{1.1333333333333333, 1.3466666666666667, 1.6048, 1.8836266666666668, 2.1664670476190477}},
+		"adam": {NewAdam(0.1, 0, 0, 0), 1,
+			[]float64{1.0999999995, 1.1998335133789073, 1.2993766071878863, 1.3984951036804671, 1.4970442174070142}},
+		"rmsprop": {NewRMSProp(0.1, 0, 0), 1,
+			[]float64{1.316227761016838, 1.5261246792335577, 1.6912821243666503, 1.8301601794254032, 1.9510726880676248}},
+		"adagrad": {NewAdagrad(0.1, 0), 1,
+			[]float64{1.0999999995, 1.1688749454513248, 1.2241784840949717, 1.2714429611933615, 1.313238400770295}},
+		"adadelta": {NewAdadelta(0, 0, 0), 1,
+			[]float64{1.0044721247747017, 1.0089962791001141, 1.0135522273915918, 1.0181298882808774, 1.0227232264724395}},
+		"adamw": {NewAdamW(0.1, 0, 0, 0, 0.1), 1,
+			[]float64{1.0899999995, 1.1789535527308885, 1.2667659665721782, 1.3533379841627933, 1.4385660208380533}},
+		"adamw without decay": {NewAdamW(0.1, 0, 0, 0, 0), 1,
+			[]float64{1.0999999995, 1.1998335133789073, 1.2993766071878863, 1.3984951036804671, 1.4970442174070142}},
+		"nadam": {NewNadam(0.1, 0, 0, 0), 1,
+			[]float64{1.1473684203157895, 1.2608785245409015, 1.365715813136246, 1.4666635051621468, 1.5652006295545937}},
+		"amsgrad": {NewAMSGrad(0.1, 0.9, 0.5, 0), 2.5,
+			[]float64{2.599999998, 2.70263335022513, 2.7997741603233184, 2.886019872094139, 2.9595394842122977, 3.020102567856053, 3.0682340469653737, 3.104824952718587}},
+	} {
+		assert.InDeltaSlice(t, tc.expected, descend(tc.solver, tc.start, len(tc.expected)), 1e-7, name)
+	}
+}
+
+func Test_SolverStateRoundTrip(t *testing.T) {
+	for name, solver := range map[string]func() Solver{
+		"sgd":      func() Solver { return NewSGD(0.1, 0.9, 0, true) },
+		"adam":     func() Solver { return NewAdam(0.1, 0, 0, 0) },
+		"rmsprop":  func() Solver { return NewRMSProp(0.1, 0, 0) },
+		"adagrad":  func() Solver { return NewAdagrad(0.1, 0) },
+		"adadelta": func() Solver { return NewAdadelta(0, 0, 0) },
+		"adamw":    func() Solver { return NewAdamW(0.1, 0, 0, 0, 0) },
+		"nadam":    func() Solver { return NewNadam(0.1, 0, 0, 0) },
+		"amsgrad":  func() Solver { return NewAMSGrad(0.1, 0, 0.5, 0) },
+	} {
+		original := solver()
+		descend(original, 1, 3)
+
+		restored := solver()
+		restored.Init(1)
+		assert.NoError(t, restored.(Stateful).SetState(original.(Stateful).State()), name)
+		assert.Equal(t, original.Update(1.5, -1.5, 4, 0), restored.Update(1.5, -1.5, 4, 0), name)
+
+		_, scheduled := restored.(Scheduled)
+		assert.True(t, scheduled, name)
+	}
+}
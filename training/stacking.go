@@ -0,0 +1,29 @@
+package training
+
+import deep "github.com/patrikeh/go-deep"
+
+// FitStacker trains the stacker of e on the members' predictions for examples,
+// leaving the members unchanged
+func FitStacker(e *deep.Ensemble, trainer Trainer, examples, validation Examples, iterations int) error {
+	stacked, err := stack(e, examples)
+	if err != nil {
+		return err
+	}
+	stackedValidation, err := stack(e, validation)
+	if err != nil {
+		return err
+	}
+	return trainer.Train(e.Stacker, stacked, stackedValidation, iterations)
+}
+
+func stack(e *deep.Ensemble, examples Examples) (Examples, error) {
+	stacked := make(Examples, len(examples))
+	for i, ex := range examples {
+		input, err := e.StackInput(ex.Input)
+		if err != nil {
+			return nil, err
+		}
+		stacked[i] = Example{Input: input, Response: ex.Response, Weight: ex.Weight}
+	}
+	return stacked, nil
+}
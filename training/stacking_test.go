@@ -0,0 +1,51 @@
+package training
+
+import (
+	"math/rand"
+	"testing"
+
+	deep "github.com/patrikeh/go-deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FitStacker(t *testing.T) {
+	rand.Seed(0)
+	examples := quadrants(200)
+
+	var members []*deep.Neural
+	for i := 0; i < 3; i++ {
+		n := deep.NewNeural(&deep.Config{
+			Inputs:     2,
+			Layout:     []int{4, 2},
+			Activation: deep.ActivationTanh,
+			Mode:       deep.ModeMultiClass,
+			Weight:     deep.NewNormal(0.5, 0),
+			Bias:       true,
+		})
+		NewTrainer(NewAdam(0.02, 0, 0, 0), 0).Train(n, examples, nil, 5)
+		members = append(members, n)
+	}
+	weights := members[0].Weights()
+
+	e, err := deep.NewEnsemble(members...)
+	assert.NoError(t, err)
+	e.Combination = deep.CombineStack
+	e.Stacker = deep.NewNeural(&deep.Config{
+		Inputs: 6,
+		Layout: []int{2},
+		Mode:   deep.ModeMultiClass,
+		Weight: deep.NewNormal(0.5, 0),
+		Bias:   true,
+	})
+
+	assert.NoError(t, FitStacker(e, NewTrainer(NewAdam(0.02, 0, 0, 0), 0), examples, nil, 20))
+	assert.Equal(t, weights, members[0].Weights())
+
+	var correct int
+	for _, ex := range examples {
+		if deep.ArgMax(e.Predict(ex.Input)) == deep.ArgMax(ex.Response) {
+			correct++
+		}
+	}
+	assert.Greater(t, float64(correct)/float64(len(examples)), 0.9)
+}
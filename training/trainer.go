@@ -1,6 +1,7 @@
 package training
 
 import (
+	"context"
 	"time"
 
 	deep "github.com/patrikeh/go-deep"
@@ -8,12 +9,17 @@ import (
 
 // Trainer is a neural network trainer
 type Trainer interface {
-	Train(n *deep.Neural, examples, validation Examples, iterations int)
+	Train(n *deep.Neural, examples, validation Examples, iterations int) error
+	TrainContext(ctx context.Context, n *deep.Neural, examples, validation Examples, iterations int) error
 }
 
 // OnlineTrainer is a basic, online network trainer
 type OnlineTrainer struct {
 	*internal
+	monitor
+	diagnosing
+	hooks
+	checkpointing
 	solver    Solver
 	printer   *StatsPrinter
 	verbosity int
@@ -29,51 +35,119 @@ func NewTrainer(solver Solver, verbosity int) *OnlineTrainer {
 }
 
 type internal struct {
+	stepper
 	deltas [][]float64
 }
 
 func newTraining(layers []*deep.Layer) *internal {
 	deltas := make([][]float64, len(layers))
+	var weights int
 	for i, l := range layers {
 		deltas[i] = make([]float64, len(l.Neurons))
+		for _, n := range l.Neurons {
+			weights += len(n.In)
+		}
 	}
 	return &internal{
-		deltas: deltas,
+		stepper: newStepper(weights),
+		deltas:  deltas,
 	}
 }
 
-// Train trains n
-func (t *OnlineTrainer) Train(n *deep.Neural, examples, validation Examples, iterations int) {
+// Train trains n. NaN or infinite values are handled according to the
+// health policy, which by default stops training and returns an error.
+func (t *OnlineTrainer) Train(n *deep.Neural, examples, validation Examples, iterations int) error {
+	return t.TrainContext(context.Background(), n, examples, validation, iterations)
+}
+
+// TrainContext trains n like Train, checking ctx before each example.
+// If ctx is done, it stops and returns an *Interrupted.
+func (t *OnlineTrainer) TrainContext(ctx context.Context, n *deep.Neural, examples, validation Examples, iterations int) error {
+	return t.train(ctx, n, nil, examples, validation, iterations)
+}
+
+// Resume continues the run of checkpoint until the given total number of epochs.
+// n must have the checkpointed layout, e.g. from deep.FromDumpE(checkpoint.Network),
+// and the examples must be those of the checkpointed run.
+func (t *OnlineTrainer) Resume(ctx context.Context, n *deep.Neural, checkpoint *Checkpoint, examples, validation Examples, iterations int) error {
+	return t.train(ctx, n, checkpoint, examples, validation, iterations)
+}
+
+func (t *OnlineTrainer) train(ctx context.Context, n *deep.Neural, checkpoint *Checkpoint, examples, validation Examples, iterations int) error {
 	t.internal = newTraining(n.Layers)
+	t.health = Health{}
+	t.diagnostics.init(n)
 
+	t.printer.rate = learningRate(t.solver)
 	t.printer.Init(n)
 	t.solver.Init(n.NumWeights())
+	start, err := t.restore(n, t.solver, checkpoint)
+	if err != nil {
+		return err
+	}
+	t.trainBegin(n, t.solver)
+	defer t.trainEnd(n)
+	if err := resumeSchedule(t.solver, checkpoint); err != nil {
+		return err
+	}
+
+	train := examples
+	if t.seeded {
+		train = make(Examples, len(examples))
+	}
 
 	ts := time.Now()
-	for i := 1; i <= iterations; i++ {
-		examples.Shuffle()
-		for j := 0; j < len(examples); j++ {
-			t.learn(n, examples[j], i)
+	for i := start; i <= iterations; i++ {
+		t.epochBegin(n, i)
+		t.shuffle(examples, train, i)
+		for j := 0; j < len(train); j++ {
+			if err := interrupted(ctx, i, j); err != nil {
+				return err
+			}
+			loss, err := t.learn(n, train[j], i)
+			if err != nil {
+				if stop, err := t.check(err, i); stop {
+					return err
+				}
+				continue
+			}
+			if t.batchEnd(n, i, j, loss, 1) {
+				return nil
+			}
 		}
+		t.diagnostics.endEpoch(n, i)
 		if t.verbosity > 0 && i%t.verbosity == 0 && len(validation) > 0 {
 			t.printer.PrintProgress(n, validation, time.Since(ts), i)
 		}
+		stop := t.epochEnd(n, i, validation)
+		if err := t.checkpoint(n, t.solver, i); err != nil {
+			return err
+		}
+		if stop {
+			return nil
+		}
 	}
+	return nil
 }
 
-func (t *OnlineTrainer) learn(n *deep.Neural, e Example, it int) {
-	n.Forward(e.Input)
-	t.calculateDeltas(n, e.Response)
-	t.update(n, it)
+// learn trains on e, returning its loss if callbacks are set
+func (t *OnlineTrainer) learn(n *deep.Neural, e Example, it int) (float64, error) {
+	if err := n.Forward(e.Input); err != nil {
+		return 0, err
+	}
+	var loss float64
+	if t.hooked() {
+		loss = exampleLoss(n, e)
+	}
+	t.calculateDeltas(n, e)
+	if err := checkDeltas(t.deltas); err != nil {
+		return 0, err
+	}
+	return loss, t.update(n, it)
 }
 
-func (t *OnlineTrainer) calculateDeltas(n *deep.Neural, ideal []float64) {
-	for i, neuron := range n.Layers[len(n.Layers)-1].Neurons {
-		t.deltas[len(n.Layers)-1][i] = deep.GetLoss(n.Config.Loss).Df(
-			neuron.Value,
-			ideal[i],
-			neuron.DActivate(neuron.Value))
-	}
+func (t *OnlineTrainer) calculateDeltas(n *deep.Neural, e Example) {
+	outputDeltas(n, e.Response, weight(n, e), t.deltas[len(n.Layers)-1])
 
 	for i := len(n.Layers) - 2; i >= 0; i-- {
 		for j, neuron := range n.Layers[i].Neurons {
@@ -86,18 +160,29 @@ func (t *OnlineTrainer) calculateDeltas(n *deep.Neural, ideal []float64) {
 	}
 }
 
-func (t *OnlineTrainer) update(n *deep.Neural, it int) {
-	var idx int
-	for i, l := range n.Layers {
-		for j := range l.Neurons {
-			for k := range l.Neurons[j].In {
-				update := t.solver.Update(l.Neurons[j].In[k].Weight,
-					t.deltas[i][j]*l.Neurons[j].In[k].In,
-					it,
-					idx)
-				l.Neurons[j].In[k].Weight += update
-				idx++
+func (t *OnlineTrainer) gradients(n *deep.Neural, examples Examples) [][][]float64 {
+	grads := n.Weights()
+	for i := range grads {
+		for j := range grads[i] {
+			clear(grads[i][j])
+		}
+	}
+	for _, e := range examples {
+		n.Forward(e.Input)
+		t.calculateDeltas(n, e)
+		for i, l := range n.Layers {
+			for j, neuron := range l.Neurons {
+				for k, s := range neuron.In {
+					grads[i][j][k] += t.deltas[i][j] * s.In
+				}
 			}
 		}
 	}
+	return grads
+}
+
+func (t *OnlineTrainer) update(n *deep.Neural, it int) error {
+	return t.apply(n, t.solver, it, t.diagnostics, t.policy == HealthSkip, func(i, j, k int) float64 {
+		return t.deltas[i][j] * n.Layers[i].Neurons[j].In[k].In
+	})
 }
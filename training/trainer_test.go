@@ -1,10 +1,12 @@
 package training
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"math/rand"
 	"testing"
+	"text/tabwriter"
 
 	deep "github.com/patrikeh/go-deep"
 	"github.com/stretchr/testify/assert"
@@ -73,11 +75,11 @@ func Test_Training(t *testing.T) {
 	rand.Seed(0)
 
 	data := Examples{
-		Example{[]float64{0}, []float64{0}},
-		Example{[]float64{0}, []float64{0}},
-		Example{[]float64{0}, []float64{0}},
-		Example{[]float64{5}, []float64{1}},
-		Example{[]float64{5}, []float64{1}},
+		Example{Input: []float64{0}, Response: []float64{0}},
+		Example{Input: []float64{0}, Response: []float64{0}},
+		Example{Input: []float64{0}, Response: []float64{0}},
+		Example{Input: []float64{5}, Response: []float64{1}},
+		Example{Input: []float64{5}, Response: []float64{1}},
 	}
 
 	n := deep.NewNeural(&deep.Config{
@@ -98,16 +100,16 @@ func Test_Training(t *testing.T) {
 }
 
 var data = []Example{
-	{[]float64{2.7810836, 2.550537003}, []float64{0}},
-	{[]float64{1.465489372, 2.362125076}, []float64{0}},
-	{[]float64{3.396561688, 4.400293529}, []float64{0}},
-	{[]float64{1.38807019, 1.850220317}, []float64{0}},
-	{[]float64{3.06407232, 3.005305973}, []float64{0}},
-	{[]float64{7.627531214, 2.759262235}, []float64{1}},
-	{[]float64{5.332441248, 2.088626775}, []float64{1}},
-	{[]float64{6.922596716, 1.77106367}, []float64{1}},
-	{[]float64{8.675418651, -0.242068655}, []float64{1}},
-	{[]float64{7.673756466, 3.508563011}, []float64{1}},
+	{Input: []float64{2.7810836, 2.550537003}, Response: []float64{0}},
+	{Input: []float64{1.465489372, 2.362125076}, Response: []float64{0}},
+	{Input: []float64{3.396561688, 4.400293529}, Response: []float64{0}},
+	{Input: []float64{1.38807019, 1.850220317}, Response: []float64{0}},
+	{Input: []float64{3.06407232, 3.005305973}, Response: []float64{0}},
+	{Input: []float64{7.627531214, 2.759262235}, Response: []float64{1}},
+	{Input: []float64{5.332441248, 2.088626775}, Response: []float64{1}},
+	{Input: []float64{6.922596716, 1.77106367}, Response: []float64{1}},
+	{Input: []float64{8.675418651, -0.242068655}, Response: []float64{1}},
+	{Input: []float64{7.673756466, 3.508563011}, Response: []float64{1}},
 }
 
 func Test_Prediction(t *testing.T) {
@@ -150,16 +152,16 @@ func Test_CrossVal(t *testing.T) {
 
 func Test_MultiClass(t *testing.T) {
 	var data = []Example{
-		{[]float64{2.7810836, 2.550537003}, []float64{1, 0}},
-		{[]float64{1.465489372, 2.362125076}, []float64{1, 0}},
-		{[]float64{3.396561688, 4.400293529}, []float64{1, 0}},
-		{[]float64{1.38807019, 1.850220317}, []float64{1, 0}},
-		{[]float64{3.06407232, 3.005305973}, []float64{1, 0}},
-		{[]float64{7.627531214, 2.759262235}, []float64{0, 1}},
-		{[]float64{5.332441248, 2.088626775}, []float64{0, 1}},
-		{[]float64{6.922596716, 1.77106367}, []float64{0, 1}},
-		{[]float64{8.675418651, -0.242068655}, []float64{0, 1}},
-		{[]float64{7.673756466, 3.508563011}, []float64{0, 1}},
+		{Input: []float64{2.7810836, 2.550537003}, Response: []float64{1, 0}},
+		{Input: []float64{1.465489372, 2.362125076}, Response: []float64{1, 0}},
+		{Input: []float64{3.396561688, 4.400293529}, Response: []float64{1, 0}},
+		{Input: []float64{1.38807019, 1.850220317}, Response: []float64{1, 0}},
+		{Input: []float64{3.06407232, 3.005305973}, Response: []float64{1, 0}},
+		{Input: []float64{7.627531214, 2.759262235}, Response: []float64{0, 1}},
+		{Input: []float64{5.332441248, 2.088626775}, Response: []float64{0, 1}},
+		{Input: []float64{6.922596716, 1.77106367}, Response: []float64{0, 1}},
+		{Input: []float64{8.675418651, -0.242068655}, Response: []float64{0, 1}},
+		{Input: []float64{7.673756466, 3.508563011}, Response: []float64{0, 1}},
 	}
 
 	n := deep.NewNeural(&deep.Config{
@@ -199,10 +201,10 @@ func Test_or(t *testing.T) {
 		Bias:       true,
 	})
 	permutations := Examples{
-		{[]float64{0, 0}, []float64{0}},
-		{[]float64{1, 0}, []float64{1}},
-		{[]float64{0, 1}, []float64{1}},
-		{[]float64{1, 1}, []float64{1}},
+		{Input: []float64{0, 0}, Response: []float64{0}},
+		{Input: []float64{1, 0}, Response: []float64{1}},
+		{Input: []float64{0, 1}, Response: []float64{1}},
+		{Input: []float64{1, 1}, Response: []float64{1}},
 	}
 
 	trainer := NewTrainer(NewSGD(0.5, 0, 0, false), 10)
@@ -225,10 +227,10 @@ func Test_xor(t *testing.T) {
 		Bias:       true,
 	})
 	permutations := Examples{
-		{[]float64{0, 0}, []float64{0}},
-		{[]float64{1, 0}, []float64{1}},
-		{[]float64{0, 1}, []float64{1}},
-		{[]float64{1, 1}, []float64{0}},
+		{Input: []float64{0, 0}, Response: []float64{0}},
+		{Input: []float64{1, 0}, Response: []float64{1}},
+		{Input: []float64{0, 1}, Response: []float64{1}},
+		{Input: []float64{1, 1}, Response: []float64{0}},
 	}
 
 	trainer := NewTrainer(NewSGD(1.0, 0.1, 1e-6, false), 50)
@@ -242,3 +244,78 @@ func Test_xor(t *testing.T) {
 func printResult(ideal, actual []float64) {
 	fmt.Printf("want: %+v have: %+v\n", ideal, actual)
 }
+
+type halfAbsolute struct{ deep.MeanAbsolute }
+
+var lossHalfAbsolute = deep.RegisterLoss("half-mae", halfAbsolute{})
+
+func (l halfAbsolute) F(estimate, ideal [][]float64) float64 {
+	return l.MeanAbsolute.F(estimate, ideal) / 2
+}
+
+func (l halfAbsolute) Objective(estimate, ideal []float64) float64 {
+	return l.MeanAbsolute.Objective(estimate, ideal)
+}
+
+func Test_RegisteredLoss(t *testing.T) {
+	rand.Seed(0)
+	loss := lossHalfAbsolute
+
+	n := deep.NewNeural(&deep.Config{
+		Inputs:     2,
+		Layout:     []int{3, 1},
+		Activation: deep.ActivationTanh,
+		Mode:       deep.ModeRegression,
+		Loss:       loss,
+		Weight:     deep.NewUniform(0.5, 0),
+		Bias:       true,
+	})
+	assert.Less(t, GradientCheck(n, data, 0).Max(), 1e-4)
+
+	var buf bytes.Buffer
+	trainer := NewTrainer(NewSGD(0.05, 0.1, 0, false), 0)
+	trainer.printer = &StatsPrinter{w: tabwriter.NewWriter(&buf, 16, 0, 3, ' ', 0)}
+	trainer.Train(n, data, data, 500)
+	trainer.printer.PrintProgress(n, data, 0, 500)
+
+	assert.Contains(t, buf.String(), "Loss (half-mae)")
+	assert.Less(t, crossValidate(n, data), 0.1)
+}
+
+func Test_MultiHead(t *testing.T) {
+	rand.Seed(0)
+	n := deep.NewNeural(&deep.Config{
+		Inputs:     2,
+		Layout:     []int{8, 3},
+		Activation: deep.ActivationTanh,
+		Weight:     deep.NewNormal(0.5, 0),
+		Bias:       true,
+		Heads: []deep.Head{
+			{Name: "quadrant", Outputs: 2, Mode: deep.ModeMultiClass},
+			{Name: "sum", Outputs: 1, Mode: deep.ModeRegression, Weight: 2},
+		},
+	})
+
+	var examples Examples
+	for i := 0; i < 100; i++ {
+		x, y := rand.Float64()*2-1, rand.Float64()*2-1
+		quadrant := []float64{1, 0}
+		if x > 0 {
+			quadrant = []float64{0, 1}
+		}
+		examples = append(examples, Example{Input: []float64{x, y}, Response: append(quadrant, (x+y)/2)})
+	}
+	assert.Less(t, GradientCheck(n, examples[:5], 0).Max(), 1e-4)
+
+	var buf bytes.Buffer
+	trainer := NewBatchTrainer(NewAdam(0.01, 0, 0, 0), 0, 10, 2)
+	trainer.printer = &StatsPrinter{w: tabwriter.NewWriter(&buf, 16, 0, 3, ' ', 0)}
+	assert.NoError(t, trainer.Train(n, examples, examples, 300))
+	trainer.printer.PrintProgress(n, examples, 0, 300)
+
+	assert.Contains(t, buf.String(), "quadrant Loss (CE)")
+	assert.Contains(t, buf.String(), "quadrant Accuracy")
+	assert.Contains(t, buf.String(), "sum Loss (MSE)")
+	assert.Greater(t, accuracy(n, examples, 0, 2), 0.95)
+	assert.Less(t, headLosses(n, examples)[1], 0.01)
+}
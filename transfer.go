@@ -0,0 +1,91 @@
+package deep
+
+// Freeze excludes the synapses of the given layers from training,
+// or of all layers if none are given
+func (n *Neural) Freeze(layers ...int) {
+	n.setFrozen(true, layers)
+}
+
+// Unfreeze makes the synapses of the given layers trainable again,
+// or of all layers if none are given
+func (n *Neural) Unfreeze(layers ...int) {
+	n.setFrozen(false, layers)
+}
+
+// FreezeTrunk freezes every layer but the output layer
+func (n *Neural) FreezeTrunk() {
+	for i := 0; i < len(n.Layers)-1; i++ {
+		n.Freeze(i)
+	}
+}
+
+// FreezeBiases excludes all bias synapses from training
+func (n *Neural) FreezeBiases() {
+	for _, b := range n.Biases {
+		for _, s := range b {
+			s.Frozen = true
+		}
+	}
+}
+
+// IsFrozen reports whether every synapse of layer i is frozen
+func (n *Neural) IsFrozen(i int) bool {
+	for _, neuron := range n.Layers[i].Neurons {
+		for _, s := range neuron.In {
+			if !s.Frozen {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (n *Neural) setFrozen(frozen bool, layers []int) {
+	if len(layers) == 0 {
+		for i := range n.Layers {
+			layers = append(layers, i)
+		}
+	}
+	for _, i := range layers {
+		for _, neuron := range n.Layers[i].Neurons {
+			for _, s := range neuron.In {
+				s.Frozen = frozen
+			}
+		}
+	}
+}
+
+// Transfer returns a copy of n whose output layer is replaced by a freshly
+// initialized one with the given number of outputs and mode. The trunk keeps
+// its weights, pruning and freezing. The loss is reset to the mode's default
+// if the mode changes, and class weights, heads and the target scaler are dropped.
+func (n *Neural) Transfer(outputs int, mode Mode) (*Neural, error) {
+	c := *n.Config
+	c.Layout = append([]int(nil), n.Config.Layout...)
+	c.Layout[len(c.Layout)-1] = outputs
+	c.Mode = mode
+	if mode != n.Config.Mode {
+		c.Loss = LossNone
+	}
+	c.ClassWeights = nil
+	c.Heads = nil
+	c.TargetScaler = nil
+
+	t, err := NewNeuralE(&c)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(n.Layers)-1; i++ {
+		for j, neuron := range n.Layers[i].Neurons {
+			for k, s := range neuron.In {
+				*t.Layers[i].Neurons[j].In[k] = Synapse{
+					Weight: s.Weight,
+					IsBias: s.IsBias,
+					Pruned: s.Pruned,
+					Frozen: s.Frozen,
+				}
+			}
+		}
+	}
+	return t, nil
+}
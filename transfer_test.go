@@ -0,0 +1,78 @@
+package deep
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Freeze(t *testing.T) {
+	n := NewNeural(&Config{
+		Inputs:     2,
+		Layout:     []int{3, 3, 2},
+		Activation: ActivationTanh,
+		Mode:       ModeMultiClass,
+		Bias:       true,
+	})
+
+	n.FreezeTrunk()
+	assert.True(t, n.IsFrozen(0))
+	assert.True(t, n.IsFrozen(1))
+	assert.False(t, n.IsFrozen(2))
+	assert.False(t, n.Layers[0].Neurons[0].In[0].Trainable())
+	assert.True(t, n.Layers[2].Neurons[0].In[0].Trainable())
+
+	n.Unfreeze(1)
+	assert.True(t, n.IsFrozen(0))
+	assert.False(t, n.IsFrozen(1))
+
+	n.Unfreeze()
+	n.FreezeBiases()
+	for i, l := range n.Layers {
+		for _, neuron := range l.Neurons {
+			for _, s := range neuron.In {
+				assert.Equal(t, s.IsBias, s.Frozen)
+			}
+		}
+		assert.False(t, n.IsFrozen(i))
+	}
+}
+
+func Test_Transfer(t *testing.T) {
+	rand.Seed(0)
+	n := NewNeural(&Config{
+		Inputs:     2,
+		Layout:     []int{4, 3, 10},
+		Activation: ActivationTanh,
+		Mode:       ModeMultiClass,
+		Loss:       LossSmoothedCrossEntropy,
+		Weight:     NewNormal(1, 0),
+		Bias:       true,
+	})
+	n.Freeze(0)
+
+	transferred, err := n.Transfer(1, ModeBinary)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 3, 10}, n.Config.Layout)
+	assert.Equal(t, []int{4, 3, 1}, transferred.Config.Layout)
+	assert.Equal(t, LossBinaryCrossEntropy, transferred.Config.Loss)
+	assert.Equal(t, ActivationSigmoid, transferred.Layers[2].A)
+	assert.Len(t, transferred.Predict([]float64{1, 2}), 1)
+
+	weights, transferredWeights := n.Weights(), transferred.Weights()
+	assert.Equal(t, weights[:2], transferredWeights[:2])
+	assert.Len(t, transferredWeights[2], 1)
+	assert.True(t, transferred.IsFrozen(0))
+	assert.False(t, transferred.IsFrozen(1))
+
+	transferred.Layers[0].Neurons[0].In[0].Weight = 42
+	assert.NotEqual(t, 42., n.Layers[0].Neurons[0].In[0].Weight)
+
+	same, err := n.Transfer(5, ModeMultiClass)
+	assert.NoError(t, err)
+	assert.Equal(t, LossSmoothedCrossEntropy, same.Config.Loss)
+
+	_, err = n.Transfer(0, ModeMultiClass)
+	assert.Error(t, err)
+}
@@ -123,6 +123,11 @@ func Softmax[f ~float64](xx []f) []f {
 	return out
 }
 
+// IsFinite reports whether x is neither NaN nor infinite
+func IsFinite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
 // Round to nearest integer
 func Round[f ~float64](x f) f {
 	return f(math.Floor(float64(x + .5)))
@@ -61,7 +61,7 @@ func Test_Dot(t *testing.T) {
 }
 
 func Test_Sgn(t *testing.T) {
-	assert.Equal(t, Sgn(0), 0.)
-	assert.Equal(t, Sgn(-5), -1.)
-	assert.Equal(t, Sgn(3), 1.)
+	assert.Equal(t, Sgn(0.), 0.)
+	assert.Equal(t, Sgn(-5.), -1.)
+	assert.Equal(t, Sgn(3.), 1.)
 }